@@ -0,0 +1,152 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/edgexfoundry/support-domain-go"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Limit on alerts posted to a webhook unless set with WithAlertLimit, so an error loop
+// doesn't become an alert storm
+const (
+	defaultMaxAlerts     = 5
+	defaultAlertInterval = time.Minute
+)
+
+// Posts an alert to a webhook for every entry at or above a level, at most as many per interval
+// as WithAlertLimit allows
+type alertSink struct {
+	url      string
+	minLevel support_domain.LogLevel
+
+	mutex      sync.Mutex
+	windowEnd  time.Time
+	sent       int
+	suppressed int
+}
+
+// Alert as posted to the webhook
+type alert struct {
+	Service   string                  `json:"service"`
+	Level     support_domain.LogLevel `json:"level"`
+	Message   string                  `json:"message"`
+	Timestamp string                  `json:"timestamp"`
+	// Alerts left out since the previous one because of the rate limit
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+// Also post a short JSON alert (service, level, message and timestamp) to the webhook at url
+// for each entry at or above minLevel, like ERROR, separately from the logging service.
+// At most 5 alerts are posted a minute (see WithAlertLimit), the next alert says how many
+// were left out. Alerts are posted in the background through the send queue, once, and
+// failures go to the error handler.
+// The headers and authorization of the logging service aren't sent to the webhook.
+func WithAlertWebhook(url string, minLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		sink := &alertSink{url: url}
+		if err := setOutputLevel(&sink.minLevel, minLevel); err != nil {
+			return err
+		}
+		lc.addSink(sink)
+		return nil
+	}
+}
+
+func (s *alertSink) send(lc LoggingClient, entry LogEntry) error {
+	if !atLeast(entry.Level, s.minLevel) {
+		return nil
+	}
+
+	suppressed, ok := s.allow(lc.now(), lc.maxAlerts, lc.alertInterval)
+	if !ok {
+		return nil
+	}
+
+	body, err := json.Marshal(alert{
+		Service:    entry.OriginService,
+		Level:      entry.Level,
+		Message:    entry.Message,
+		Timestamp:  time.Unix(0, entry.Created*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano),
+		Suppressed: suppressed,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	// Logging mustn't wait for the webhook. Once the client is closed the alert is refused.
+	lc.sends.start()
+	if err := lc.queue.push(queuedSend{lc: lc, ctx: context.Background(), post: func() error {
+		_, err := lc.doRequest(req)
+		if err != nil {
+			lc.reportError(err)
+		}
+		return err
+	}}); err != nil {
+		lc.sends.finish()
+	}
+	return nil
+}
+
+// Whether another alert may be posted now, and how many were suppressed before it
+func (s *alertSink) allow(now time.Time, maxAlerts int, interval time.Duration) (int, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !now.Before(s.windowEnd) {
+		s.windowEnd = now.Add(interval)
+		s.sent = 0
+	}
+	if s.sent >= maxAlerts {
+		s.suppressed++
+		return 0, false
+	}
+
+	s.sent++
+	suppressed := s.suppressed
+	s.suppressed = 0
+	return suppressed, true
+}
+
+// Alerts still queued are posted, and waited for, by Close along with the other sends
+func (s *alertSink) close(lc LoggingClient) error {
+	return nil
+}
+
+// Post at most alerts alerts to the WithAlertWebhook webhook per interval, instead of 5 a minute
+func WithAlertLimit(alerts int, interval time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		if alerts < 1 || interval <= 0 {
+			return errors.New("the alert limit needs at least 1 alert and an interval greater than 0")
+		}
+		lc.maxAlerts = alerts
+		lc.alertInterval = interval
+		return nil
+	}
+}
@@ -0,0 +1,62 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package logger
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+func TestAlertLimit(t *testing.T) {
+	webhook := newCollectingServer(t)
+	clock := &manualClock{now: time.Date(2017, 11, 20, 10, 0, 0, 0, time.UTC)}
+	lc, err := New("test", WithWriter(io.Discard), WithAlertWebhook(webhook.URL, support_domain.ERROR),
+		WithAlertLimit(2, time.Hour), WithSendQueue(10, 1, BlockWhenFull), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{"first", "second", "third"} {
+		lc.Error(msg)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Nothing is posted once the client is closed, even when the limit allows it
+	clock.advance(time.Hour)
+	lc.Error("after close")
+	time.Sleep(50 * time.Millisecond)
+
+	if got, want := webhook.received(), []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("webhook received %q, want %q", got, want)
+	}
+}
+
+func TestAlertLimitInvalid(t *testing.T) {
+	if _, err := New("test", WithAlertLimit(0, time.Minute)); err == nil {
+		t.Error("no error for a limit of 0 alerts")
+	}
+	if _, err := New("test", WithAlertLimit(5, 0)); err == nil {
+		t.Error("no error for an interval of 0")
+	}
+}
@@ -0,0 +1,54 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+// Label of the entries logged with Audit
+const AuditLabel = "audit"
+
+// Log an audit entry: that actor did action on resource, with the given outcome (like "success"
+// or "denied"). It is an INFO entry labeled audit (and with the extra labels), with actor,
+// action, resource and outcome fields so every audit entry has the same structure.
+// Audit entries are logged whatever the configured levels, are never sampled, deduplicated
+// or batched, and are sent to the logging service before Audit returns, with its error.
+func (lc LoggingClient) Audit(action string, actor string, resource string, outcome string, extra ...string) error {
+	lc = lc.WithFields(map[string]interface{}{
+		"actor":    actor,
+		"action":   action,
+		"resource": resource,
+		"outcome":  outcome,
+	})
+
+	lc.allLevels = true
+	lc.stdOutLevel = ""
+	lc.fileLevel = ""
+	lc.remoteLevel = ""
+	lc.remoteInclude = nil
+	lc.remoteExclude = nil
+	lc.sampler = nil
+	lc.deduplicator = nil
+	lc.Synchronous = true
+	lc.BatchSize = 0
+
+	msg := fmt.Sprintf("audit: %s %s %s: %s", actor, action, resource, outcome)
+	return lc.log(support_domain.INFO, msg, append([]string{AuditLabel}, extra...))
+}
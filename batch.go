@@ -0,0 +1,170 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Batching of the sinks posting entries in bulk (like Elasticsearch and OTLP),
+// when the client isn't configured with WithBatching
+const (
+	defaultBulkSize          = 100
+	defaultBulkFlushInterval = 5 * time.Second
+)
+
+// Log entries waiting to be sent to the logging service together
+type logBatch struct {
+	mutex   sync.Mutex
+	entries []LogEntry
+	timer   *time.Timer
+}
+
+// Add an entry to the batch, returning the whole batch once it holds size entries.
+// The first entry of a batch starts a timer that calls flush after interval.
+func (b *logBatch) add(entry LogEntry, size int, interval time.Duration, flush func()) []LogEntry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.entries = append(b.entries, entry)
+	if len(b.entries) >= size {
+		return b.take()
+	}
+
+	if len(b.entries) == 1 && interval > 0 {
+		b.timer = time.AfterFunc(interval, flush)
+	}
+	return nil
+}
+
+// Add the entry to the batch of a sink, which is posted in the background with the request
+// from newPost once it is full, or after the flush interval
+func (lc LoggingClient) addToSinkBatch(batch *logBatch, entry LogEntry, newPost func(LoggingClient, []LogEntry) (*http.Request, error)) error {
+	size, interval := lc.BatchSize, lc.FlushInterval
+	if size <= 1 {
+		size, interval = defaultBulkSize, defaultBulkFlushInterval
+	}
+
+	entries := batch.add(entry, size, interval, func() {
+		lc.sends.start()
+		defer lc.sends.finish()
+
+		if err := lc.flushSinkBatch(batch, newPost); err != nil {
+			lc.reportError(err)
+		}
+	})
+	if entries == nil {
+		return nil
+	}
+
+	req, err := newPost(lc, entries)
+	if err != nil {
+		return err
+	}
+
+	// The batch holds entries from other requests, so it is sent regardless of their contexts
+	lc.sends.start()
+	if err := lc.queue.push(queuedSend{lc: lc, ctx: context.Background(), request: req}); err != nil {
+		lc.sends.finish()
+	}
+	return nil
+}
+
+// Post the pending entries of a sink now, waiting for the result
+func (lc LoggingClient) flushSinkBatch(batch *logBatch, newPost func(LoggingClient, []LogEntry) (*http.Request, error)) error {
+	entries := batch.flush()
+	if len(entries) == 0 {
+		return nil
+	}
+
+	req, err := newPost(lc, entries)
+	if err != nil {
+		return err
+	}
+	return lc.deliverOrSpool(req)
+}
+
+// Remove and return all the pending entries
+func (b *logBatch) flush() []LogEntry {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.take()
+}
+
+// Must be called with the mutex held
+func (b *logBatch) take() []LogEntry {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	entries := b.entries
+	b.entries = nil
+	return entries
+}
+
+// Tracks remote sends that are still in flight
+type sendTracker struct {
+	mutex   sync.Mutex
+	pending int
+	done    chan struct{}
+}
+
+// Record that a send has started
+func (t *sendTracker) start() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.pending == 0 {
+		t.done = make(chan struct{})
+	}
+	t.pending++
+}
+
+// Record that a send has finished, successfully or not
+func (t *sendTracker) finish() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.pending--
+	if t.pending == 0 {
+		close(t.done)
+	}
+}
+
+// Wait until no sends are in flight, returning false if that takes longer than timeout
+func (t *sendTracker) wait(timeout time.Duration) bool {
+	t.mutex.Lock()
+	if t.pending == 0 {
+		t.mutex.Unlock()
+		return true
+	}
+	done := t.done
+	t.mutex.Unlock()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Synchronous client logging to a server answering every request with status
+func statusClient(t *testing.T, status int, opts ...Option) LoggingClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	lc, err := New("test", append([]Option{WithWriter(io.Discard), WithRemote(server.URL), WithSynchronous(), WithErrorHandler(func(error) {})}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return lc
+}
+
+func TestNoCircuitBreakerByDefault(t *testing.T) {
+	lc := statusClient(t, http.StatusInternalServerError)
+	for i := 0; i < 10; i++ {
+		if err := lc.Info("message"); errors.Is(err, ErrCircuitOpen) || err == nil {
+			t.Fatalf("send %d returned %v, want the status error", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerOpensOnServerErrors(t *testing.T) {
+	lc := statusClient(t, http.StatusInternalServerError, WithCircuitBreaker(3, time.Hour))
+	for i := 0; i < 3; i++ {
+		lc.Info("message")
+	}
+	if err := lc.Info("message"); err != ErrCircuitOpen {
+		t.Fatalf("send after 3 failures returned %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresRejections(t *testing.T) {
+	lc := statusClient(t, http.StatusBadRequest, WithCircuitBreaker(3, time.Hour))
+	for i := 0; i < 10; i++ {
+		if err := lc.Info("message"); err == ErrCircuitOpen {
+			t.Fatalf("circuit opened by 4xx answers after %d sends", i)
+		}
+	}
+}
+
+func TestRetryWaitEndsWithContext(t *testing.T) {
+	lc := statusClient(t, http.StatusInternalServerError, WithRetries(3, time.Hour))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	var statusErr *RemoteStatusError
+	if err := lc.InfoCtx(ctx, "message"); !errors.As(err, &statusErr) {
+		t.Errorf("send returned %v, want the status error", err)
+	}
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("send returned after %s, not when the context ended", waited)
+	}
+}
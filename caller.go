@@ -0,0 +1,100 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Import path of this package, whose frames are skipped when looking for the caller
+var packagePath = reflect.TypeOf(LoggingClient{}).PkgPath()
+
+// Maximum number of frames searched for the caller
+const maxCallerDepth = 32
+
+// Get the file:line of the code that logged, skipping the frames of this package, of
+// the standard loggers that forward to it (through Writer or the slog handler) and of the runtime.
+// The standard logger's own caller detection can't be used since it always finds this package.
+func callerLocation() string {
+	var pcs [maxCallerDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isLoggingFrame(frame.Function) {
+			return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return "???:0"
+		}
+	}
+}
+
+// Limits on the stack trace attached to an entry
+const (
+	maxStackDepth = 32
+	maxStackSize  = 8 * 1024
+)
+
+// Get the stack of the code that logged, like runtime.Stack prints it, leaving out the frames
+// of this package and the standard loggers. Cut short after maxStackDepth frames or maxStackSize bytes.
+func callerStack() string {
+	// Room for the frames skipped as well
+	var pcs [maxStackDepth + maxCallerDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var buf strings.Builder
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if depth > 0 || !isLoggingFrame(frame.Function) {
+			if depth >= maxStackDepth || buf.Len() >= maxStackSize {
+				buf.WriteString("...\n")
+				break
+			}
+			fmt.Fprintf(&buf, "%s()\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+			depth++
+		}
+		if !more {
+			break
+		}
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Frames of this package, the standard loggers, and the runtime, which is on top of the
+// stack when logging from a deferred function during a panic
+func isLoggingFrame(function string) bool {
+	pkg := functionPackage(function)
+	return pkg == packagePath || pkg == "log" || pkg == "log/slog" || pkg == "runtime"
+}
+
+// Package part of a qualified function name like "github.com/a/b.Type.Method"
+func functionPackage(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[lastSlash+1:], "."); dot >= 0 {
+		return function[:lastSlash+1+dot]
+	}
+	return function
+}
@@ -0,0 +1,86 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger_test
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/support-domain-go"
+	"github.com/edgexfoundry/support-logging-client-go"
+)
+
+// Log a warning the way the test names, returning the line it was logged from
+func logWarning(lc logger.LoggingClient, how string) int {
+	switch how {
+	case "method":
+		_, _, line, _ := runtime.Caller(0)
+		lc.Warn("message")
+		return line + 1
+	case "writer":
+		_, _, line, _ := runtime.Caller(0)
+		log.New(lc.Writer(support_domain.WARN), "", 0).Print("message")
+		return line + 1
+	default:
+		_, _, line, _ := runtime.Caller(0)
+		slog.New(logger.NewSlogHandler(lc)).Warn("message")
+		return line + 1
+	}
+}
+
+func TestCallerLocation(t *testing.T) {
+	for _, how := range []string{"method", "writer", "slog"} {
+		var stdout bytes.Buffer
+		lc, err := logger.New("test", logger.WithWriter(&stdout), logger.WithTimeFormat(""))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		line := logWarning(lc, how)
+		want := fmt.Sprintf("WARN: caller_test.go:%d: message", line)
+		if got := strings.TrimSpace(stdout.String()); got != want {
+			t.Errorf("logged through %s as %q, want %q", how, got, want)
+		}
+	}
+}
+
+// Panic and recover, returning the line of the panic
+func panicAndRecover(lc logger.LoggingClient) (line int) {
+	defer lc.RecoverAndContinue()
+	_, _, line, _ = runtime.Caller(0)
+	panic("failed")
+}
+
+func TestCallerOfPanic(t *testing.T) {
+	var stdout bytes.Buffer
+	lc, err := logger.New("test", logger.WithWriter(&stdout), logger.WithTimeFormat(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	line := panicAndRecover(lc)
+	want := fmt.Sprintf("ERROR: caller_test.go:%d: panic: failed", line+1)
+	if got := strings.SplitN(stdout.String(), "\n", 2)[0]; got != want {
+		t.Errorf("panic logged as %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,43 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"time"
+)
+
+// Source of the current time, for the timestamps of entries and lines and for time based
+// rotation. Tests can supply a fixed or controllable clock with WithClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// The system clock, used unless the client is given another Clock
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// The current time according to the client's clock
+func (lc LoggingClient) now() time.Time {
+	if lc.clock == nil {
+		return time.Now()
+	}
+	return lc.clock.Now()
+}
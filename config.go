@@ -0,0 +1,67 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"errors"
+)
+
+// Logging settings as they appear in the configuration of an EdgeX service, like
+//
+//	[Logging]
+//	EnableRemote = true
+//	File = "./logs/edgex-core-data.log"
+//	Level = "INFO"
+//	RemoteURL = "http://localhost:48061/api/v1/logs"
+type LoggingConfig struct {
+	// Send logs to the logging service at RemoteURL
+	EnableRemote bool
+	// Path of the file to write logs to, none if empty
+	File string
+	// Minimum level to log, everything if empty
+	Level string
+	// Full path to the logging service api, needed when EnableRemote is set
+	RemoteURL string
+}
+
+// Create a new logging client for the owning service from its logging configuration, with
+// the defaults for anything not set. An invalid level or remote url is returned as an error.
+func NewClientFromConfig(owningServiceName string, config LoggingConfig) (LoggingClient, error) {
+	var opts []Option
+
+	if config.Level != "" {
+		logLevel, err := ParseLogLevel(config.Level)
+		if err != nil {
+			return LoggingClient{}, err
+		}
+		opts = append(opts, WithLevel(logLevel))
+	}
+
+	if config.File != "" {
+		opts = append(opts, WithLogFile(config.File))
+	}
+
+	if config.EnableRemote {
+		if config.RemoteURL == "" {
+			return LoggingClient{}, errors.New("remote logging is enabled, but no RemoteURL is configured")
+		}
+		opts = append(opts, WithRemote(config.RemoteURL))
+	}
+
+	return New(owningServiceName, opts...)
+}
@@ -0,0 +1,73 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+func TestNewClientFromConfigDefaults(t *testing.T) {
+	lc, err := NewClientFromConfig("test", LoggingConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.minLevel() != support_domain.TRACE || lc.remoteUrl() != "" || lc.logFilePath() != "" || !lc.EnableStdOut {
+		t.Errorf("empty config gave %s", lc.Describe())
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	lc, err := NewClientFromConfig("test", LoggingConfig{
+		EnableRemote: true,
+		File:         path,
+		Level:        "info",
+		RemoteURL:    "http://localhost:48061/api/v1/logs",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.minLevel() != support_domain.INFO || lc.remoteUrl() != "http://localhost:48061/api/v1/logs" || lc.logFilePath() != path {
+		t.Errorf("config gave %s", lc.Describe())
+	}
+
+	// The url is only used if remote logging is enabled
+	lc, err = NewClientFromConfig("test", LoggingConfig{RemoteURL: "http://localhost:48061/api/v1/logs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lc.remoteUrl() != "" {
+		t.Errorf("remote url %s used without EnableRemote", lc.remoteUrl())
+	}
+}
+
+func TestNewClientFromConfigInvalid(t *testing.T) {
+	for name, config := range map[string]LoggingConfig{
+		"level":      {Level: "VERBOSE"},
+		"no url":     {EnableRemote: true},
+		"bad url":    {EnableRemote: true, RemoteURL: "localhost:48061"},
+		"bad scheme": {EnableRemote: true, RemoteURL: "ftp://localhost:48061/api/v1/logs"},
+	} {
+		if _, err := NewClientFromConfig("test", config); err == nil {
+			t.Errorf("invalid %s accepted", name)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+// Type of the context keys defined by this package, so they can't collide with other packages' keys
+type contextKey string
+
+// Context key the correlation ID of a request is read from, unless the client
+// was configured with another key using WithCorrelationIDKey
+const CorrelationIDKey = contextKey("correlation-id")
+
+// HTTP header carrying the correlation ID between EdgeX services
+const CorrelationIDHeader = "X-Correlation-ID"
+
+// Create a new correlation ID, a random (version 4) UUID, for a request that starts in this service
+func NewCorrelationID() string {
+	var uuid [16]byte
+	if _, err := rand.Read(uuid[:]); err != nil {
+		// The system's random source is broken, nothing sensible can be done
+		panic(err)
+	}
+	uuid[6] = (uuid[6] & 0x0f) | 0x40
+	uuid[8] = (uuid[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
+}
+
+// Get a context carrying the correlation ID under CorrelationIDKey, for the Ctx logging methods
+// and anything else handling the request
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, CorrelationIDKey, id)
+}
+
+// Log an INFO level message, correlated with the request the context belongs to
+func (lc LoggingClient) InfoCtx(ctx context.Context, msg string, labels ...string) error {
+	return lc.logWithContext(ctx, support_domain.INFO, msg, labels)
+}
+
+// Log a TRACE level message, correlated with the request the context belongs to
+func (lc LoggingClient) TraceCtx(ctx context.Context, msg string, labels ...string) error {
+	return lc.logWithContext(ctx, support_domain.TRACE, msg, labels)
+}
+
+// Log a DEBUG level message, correlated with the request the context belongs to
+func (lc LoggingClient) DebugCtx(ctx context.Context, msg string, labels ...string) error {
+	return lc.logWithContext(ctx, support_domain.DEBUG, msg, labels)
+}
+
+// Log a WARN level message, correlated with the request the context belongs to
+func (lc LoggingClient) WarnCtx(ctx context.Context, msg string, labels ...string) error {
+	return lc.logWithContext(ctx, support_domain.WARN, msg, labels)
+}
+
+// Log an ERROR level message, correlated with the request the context belongs to
+func (lc LoggingClient) ErrorCtx(ctx context.Context, msg string, labels ...string) error {
+	return lc.logWithContext(ctx, support_domain.ERROR, msg, labels)
+}
+
+// Get a logger tagging every message with the correlation ID, unless the context of a Ctx method carries another
+func (lc LoggingClient) WithCorrelationID(id string) LoggingClient {
+	lc.baseCorrelationID = id
+	return lc
+}
+
+// Get the correlation ID stored in the context, or else the one given to WithCorrelationID
+func (lc LoggingClient) correlationID(ctx context.Context) string {
+	if id, ok := ctx.Value(lc.correlationIDKey).(string); ok && id != "" {
+		return id
+	}
+	return lc.baseCorrelationID
+}
+
+// Attach the fields the WithContextFields functions find in the context, like the IDs of a trace.
+// The fields are copied, as they may be shared with other entries.
+func (lc LoggingClient) addContextFields(ctx context.Context, entry *LogEntry) {
+	var fields map[string]interface{}
+	for _, contextFields := range lc.contextFields {
+		found := contextFields(ctx)
+		if len(found) == 0 {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]interface{}, len(entry.Fields)+len(found))
+			for key, value := range entry.Fields {
+				fields[key] = value
+			}
+		}
+		for key, value := range found {
+			fields[key] = value
+		}
+	}
+	if fields != nil {
+		entry.Fields = fields
+	}
+}
@@ -0,0 +1,53 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestContextFields(t *testing.T) {
+	type requestKey struct{}
+	memory := &MemorySink{}
+	lc, err := New("test", WithWriter(io.Discard), WithContextFields(func(ctx context.Context) map[string]interface{} {
+		if request, ok := ctx.Value(requestKey{}).(string); ok {
+			return map[string]interface{}{"request": request}
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc = lc.WithField("device", "thermostat")
+	lc.AddHook(memory)
+
+	lc.InfoCtx(context.WithValue(context.Background(), requestKey{}, "r1"), "with request")
+	lc.InfoCtx(context.Background(), "without request")
+
+	entries := memory.Entries()
+	if want := map[string]interface{}{"device": "thermostat", "request": "r1"}; len(entries) != 2 || !reflect.DeepEqual(entries[0].Fields, want) {
+		t.Fatalf("entries %v, want the first with fields %v", entries, want)
+	}
+	// The client's fields are shared by its entries, so aren't changed in place
+	if want := map[string]interface{}{"device": "thermostat"}; !reflect.DeepEqual(entries[1].Fields, want) {
+		t.Errorf("second entry has fields %v, want %v", entries[1].Fields, want)
+	}
+}
@@ -0,0 +1,91 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"sync"
+	"time"
+)
+
+// Collapses a message logged again and again back to back into one line and a count,
+// like syslog's "message repeated N times"
+type deduplicator struct {
+	mutex   sync.Mutex
+	window  time.Duration
+	level   support_domain.LogLevel
+	message string
+	start   time.Time
+	repeats int
+	timer   *time.Timer
+}
+
+// Whether this message should be logged. Repeats of the previous message within the window
+// are only counted; their summary is logged when another message arrives or the window closes.
+func (d *deduplicator) allow(lc LoggingClient, logLevel support_domain.LogLevel, msg string) bool {
+	// The client's clock decides the window, so it agrees with the timestamps of the lines
+	now := lc.now()
+	d.mutex.Lock()
+	if logLevel == d.level && msg == d.message && now.Sub(d.start) < d.window {
+		d.repeats++
+		if d.timer == nil {
+			d.timer = time.AfterFunc(d.window-now.Sub(d.start), func() {
+				d.windowClosed(lc)
+			})
+		}
+		d.mutex.Unlock()
+		return false
+	}
+
+	level, message, repeats := d.level, d.message, d.repeats
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	d.level, d.message, d.start, d.repeats = logLevel, msg, now, 0
+	d.mutex.Unlock()
+
+	// The summary comes before the new message, so the lines stay in order
+	lc.logRepeats(level, message, repeats)
+	return true
+}
+
+// Log the summary of the repeats in the window, and log the message again if it comes back
+func (d *deduplicator) windowClosed(lc LoggingClient) {
+	d.mutex.Lock()
+	level, message, repeats := d.level, d.message, d.repeats
+	d.level, d.message, d.repeats, d.timer = "", "", 0, nil
+	d.mutex.Unlock()
+
+	lc.logRepeats(level, message, repeats)
+}
+
+// Log how often a message was repeated, if it was
+func (lc LoggingClient) logRepeats(logLevel support_domain.LogLevel, msg string, repeats int) {
+	if repeats == 0 {
+		return
+	}
+
+	// Not deduplicated or sampled itself, and with no useful caller
+	lc.deduplicator = nil
+	lc.sampler = nil
+	lc.stdOutFormatter = withoutCaller(lc.stdOutFormatter)
+	lc.fileFormatter = withoutCaller(lc.fileFormatter)
+	lc.log(logLevel, fmt.Sprintf("%s ... repeated %d times", msg, repeats), nil)
+}
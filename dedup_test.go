@@ -0,0 +1,63 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Clock the test moves forward by hand
+type manualClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestDeduplicationUsesClientClock(t *testing.T) {
+	clock := &manualClock{now: time.Date(2017, 11, 20, 10, 0, 0, 0, time.UTC)}
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithTimeFormat(""), WithClock(clock), WithDeduplication(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc.Info("no reading")
+	clock.advance(time.Minute)
+	lc.Info("no reading")
+	clock.advance(2 * time.Hour)
+	lc.Info("no reading")
+
+	want := "INFO: no reading\nINFO: no reading ... repeated 1 times\nINFO: no reading\n"
+	if got := stdout.String(); got != want {
+		t.Errorf("stdout got %q, want %q", got, want)
+	}
+}
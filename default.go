@@ -0,0 +1,76 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"github.com/edgexfoundry/support-domain-go"
+	"sync"
+)
+
+// The client used by the package level logging functions, nil until SetDefault is called
+var (
+	defaultMutex  sync.RWMutex
+	defaultClient *LoggingClient
+)
+
+// Make lc the client the package level Info, Debug and so on log with. It can be called at
+// any time, even while other goroutines are logging: each log goes to either the old or the
+// new client. The old client isn't closed.
+func SetDefault(lc LoggingClient) {
+	defaultMutex.Lock()
+	defer defaultMutex.Unlock()
+
+	defaultClient = &lc
+}
+
+// Log with the default client, or do nothing if there is none
+func logDefault(logLevel support_domain.LogLevel, msg string, labels []string) error {
+	defaultMutex.RLock()
+	lc := defaultClient
+	defaultMutex.RUnlock()
+
+	if lc == nil {
+		return nil
+	}
+	return lc.log(logLevel, msg, labels)
+}
+
+// Log an INFO level message with the default client
+func Info(msg string, labels ...string) error {
+	return logDefault(support_domain.INFO, msg, labels)
+}
+
+// Log a TRACE level message with the default client
+func Trace(msg string, labels ...string) error {
+	return logDefault(support_domain.TRACE, msg, labels)
+}
+
+// Log a DEBUG level message with the default client
+func Debug(msg string, labels ...string) error {
+	return logDefault(support_domain.DEBUG, msg, labels)
+}
+
+// Log a WARN level message with the default client
+func Warn(msg string, labels ...string) error {
+	return logDefault(support_domain.WARN, msg, labels)
+}
+
+// Log an ERROR level message with the default client
+func Error(msg string, labels ...string) error {
+	return logDefault(support_domain.ERROR, msg, labels)
+}
@@ -0,0 +1,110 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"net/url"
+	"strings"
+)
+
+// Describe the effective configuration: the level and where logs go, like
+// "level=INFO stdout=true file=/var/log/svc.log remote=http://localhost:48061/api/v1/logs timeout=5s ...".
+// Log it once at startup to confirm the client is set up as intended.
+func (lc LoggingClient) Describe() string {
+	lc = lc.initialized()
+	parts := []string{
+		"service=" + lc.owningServiceName,
+		"level=" + string(lc.minLevel()),
+		fmt.Sprintf("stdout=%t", lc.EnableStdOut && !lc.discard) + describeLevel(lc.stdOutLevel),
+	}
+	if lc.stdErrLogger != nil {
+		parts = append(parts, "stderr=true"+describeLevel(support_domain.WARN))
+	}
+
+	if path := lc.logFilePath(); path != "" {
+		parts = append(parts, "file="+datedPath(path, lc.RotateTimeLayout, lc.now())+describeLevel(lc.fileLevel))
+	} else {
+		parts = append(parts, "file=none")
+	}
+
+	lc.mutex.Lock()
+	for _, sink := range lc.fileSinks.sinks {
+		parts = append(parts, "file="+sink.path+describeLevel(sink.minLevel))
+	}
+	lc.mutex.Unlock()
+
+	if url := lc.remoteUrl(); url != "" {
+		parts = append(parts,
+			"remote="+url+describeLevel(lc.remoteLevel),
+			fmt.Sprintf("timeout=%s", lc.RemoteTimeout),
+			fmt.Sprintf("retries=%d", lc.MaxRetries),
+			fmt.Sprintf("synchronous=%t", lc.Synchronous))
+		if lc.BatchSize > 1 {
+			parts = append(parts, fmt.Sprintf("batch=%d/%s", lc.BatchSize, lc.FlushInterval))
+		}
+	} else {
+		parts = append(parts, "remote=none")
+	}
+	if lc.localOnly {
+		parts = append(parts, "localonly=true("+envLocalOnly+")")
+	}
+
+	if lc.syslog != nil {
+		parts = append(parts, "syslog=true")
+	}
+	for _, sink := range lc.sinks {
+		switch sink := sink.(type) {
+		case *elasticsearchSink:
+			parts = append(parts, "elasticsearch="+sink.bulkUrl)
+		case *otlpSink:
+			parts = append(parts, "otlp="+sink.url)
+		case *ndjsonSink:
+			parts = append(parts, "ndjson="+sink.url)
+		case *alertSink:
+			// Webhook urls often embed a secret, so only the host is shown
+			parts = append(parts, "alert="+urlHost(sink.url)+describeLevel(sink.minLevel))
+		case customSink:
+			if stringer, ok := sink.Sink.(fmt.Stringer); ok {
+				parts = append(parts, stringer.String())
+			}
+		}
+	}
+
+	if lc.discard {
+		parts = append(parts, "discard=true")
+	}
+	return strings.Join(parts, " ")
+}
+
+// The minimum level of an output, if it has its own
+func describeLevel(minLevel support_domain.LogLevel) string {
+	if minLevel == "" {
+		return ""
+	}
+	return "(>=" + string(minLevel) + ")"
+}
+
+// Host part of a url, or the url itself if it doesn't parse
+func urlHost(rawUrl string) string {
+	if parsed, err := url.Parse(rawUrl); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return rawUrl
+}
@@ -0,0 +1,148 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Indexes log entries straight into Elasticsearch with the bulk api, in batches
+type elasticsearchSink struct {
+	bulkUrl string
+	index   string
+	batch   *logBatch
+}
+
+// Log entry as indexed in Elasticsearch
+type elasticsearchDocument struct {
+	Timestamp     string                  `json:"@timestamp"`
+	Level         support_domain.LogLevel `json:"level"`
+	Service       string                  `json:"service"`
+	Message       string                  `json:"message"`
+	Labels        []string                `json:"labels,omitempty"`
+	Fields        map[string]interface{}  `json:"fields,omitempty"`
+	CorrelationID string                  `json:"correlation_id,omitempty"`
+	Hostname      string                  `json:"hostname,omitempty"`
+	Pid           int                     `json:"pid,omitempty"`
+}
+
+// Also index log entries into the index of the Elasticsearch cluster at url, bypassing the
+// logging service. Entries are sent with the bulk api, in batches of the size and flush interval
+// set with WithBatching (or 100 entries and 5 seconds), through the same http client, headers
+// and authorization (WithBearerToken, WithBasicAuth and so on) as requests to the logging service.
+func WithElasticsearch(url string, index string) Option {
+	return func(lc *LoggingClient) error {
+		lc.addSink(&elasticsearchSink{
+			bulkUrl: strings.TrimSuffix(url, "/") + "/_bulk",
+			index:   index,
+			batch:   &logBatch{},
+		})
+		return nil
+	}
+}
+
+func (s *elasticsearchSink) send(lc LoggingClient, entry LogEntry) error {
+	return lc.addToSinkBatch(s.batch, entry, s.newPost)
+}
+
+// Index the pending entries now, waiting for the result
+func (s *elasticsearchSink) close(lc LoggingClient) error {
+	return lc.flushSinkBatch(s.batch, s.newPost)
+}
+
+// Answer of the bulk api, which is 200 even when entries failed to index
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Error *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// Build the bulk request indexing the entries
+func (s *elasticsearchSink) newPost(lc LoggingClient, entries []LogEntry) (*http.Request, error) {
+	req, err := lc.newPost(s.bulkUrl, "application/x-ndjson", s.bulkBody(entries))
+	if err != nil {
+		return nil, err
+	}
+	return withResponseCheck(req, s.checkResponse), nil
+}
+
+// Fail the request when the bulk api says entries weren't indexed, giving the first reason
+func (s *elasticsearchSink) checkResponse(body io.Reader) error {
+	// An answer that can't be read isn't taken as a failure, the status said it was accepted
+	var response bulkResponse
+	if err := json.NewDecoder(body).Decode(&response); err != nil || !response.Errors {
+		return nil
+	}
+
+	failed, reason := 0, "no reason given"
+	for _, item := range response.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			if failed++; failed == 1 {
+				reason = result.Error.Type + ": " + result.Error.Reason
+			}
+		}
+	}
+	return fmt.Errorf("elasticsearch at %s didn't index %d of %d entries: %s", s.bulkUrl, failed, len(response.Items), reason)
+}
+
+// Build the body of a bulk request: an action line then the document, for each entry
+func (s *elasticsearchSink) bulkBody(entries []LogEntry) []byte {
+	action, _ := json.Marshal(map[string]interface{}{
+		"index": map[string]string{"_index": s.index},
+	})
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		document := elasticsearchDocument{
+			Timestamp:     time.Unix(0, entry.Created*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano),
+			Level:         entry.Level,
+			Service:       entry.OriginService,
+			Message:       entry.Message,
+			Labels:        entry.Labels,
+			Fields:        entry.Fields,
+			CorrelationID: entry.CorrelationID,
+			Hostname:      entry.Hostname,
+			Pid:           entry.Pid,
+		}
+		doc, err := json.Marshal(document)
+		if err != nil {
+			// Only the fields can fail to encode, keep the rest of the entry as formatJSON does
+			document.Fields = map[string]interface{}{"fieldsError": err.Error()}
+			doc, _ = json.Marshal(document)
+		}
+		buf.Write(action)
+		buf.WriteByte('\n')
+		buf.Write(doc)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
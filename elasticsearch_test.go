@@ -0,0 +1,53 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package logger
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestElasticsearchIndexingErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"took":3,"errors":true,"items":[` +
+			`{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [fields.size]"}}},` +
+			`{"index":{"status":201}}]}`))
+	}))
+	defer server.Close()
+
+	lc, err := New("test", WithWriter(io.Discard), WithElasticsearch(server.URL, "logs"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc.Info("rejected")
+	lc.Info("indexed")
+
+	err = lc.Close()
+	if !errors.Is(err, ErrSendFailed) {
+		t.Fatalf("Close returned %v, want a send error", err)
+	}
+	if want := "didn't index 1 of 2 entries: mapper_parsing_exception: failed to parse field [fields.size]"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q doesn't contain %q", err, want)
+	}
+}
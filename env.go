@@ -0,0 +1,81 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	envLogLevel  = "EDGEX_LOG_LEVEL"
+	envRemoteUrl = "EDGEX_LOG_REMOTE_URL"
+	envLogFile   = "EDGEX_LOG_FILE"
+	envTimeout   = "EDGEX_LOG_TIMEOUT"
+)
+
+// Read by every client when it is created: set to true to keep all logs local, for
+// development and air-gapped tests, whatever remote url or transports are configured
+const envLocalOnly = "EDGEX_LOG_LOCAL_ONLY"
+
+// Whether EDGEX_LOG_LOCAL_ONLY asks for local logging only. Values other than
+// true or false (as strconv.ParseBool understands them) are ignored.
+func localOnlyFromEnv() bool {
+	localOnly, err := strconv.ParseBool(os.Getenv(envLocalOnly))
+	return err == nil && localOnly
+}
+
+// Create a new logging client for the owning service, configured from the environment:
+//   - EDGEX_LOG_LEVEL: minimum level to log (TRACE, DEBUG, INFO, WARN or ERROR)
+//   - EDGEX_LOG_REMOTE_URL: full path to the logging service api
+//   - EDGEX_LOG_FILE: path of the file to write logs to
+//   - EDGEX_LOG_TIMEOUT: time allowed for requests to the logging service, like "2s"
+//
+// Unset variables keep the defaults: everything is logged, to stdout only.
+func NewClientFromEnv(owningServiceName string) (LoggingClient, error) {
+	var opts []Option
+
+	if value, ok := os.LookupEnv(envLogLevel); ok {
+		logLevel, err := ParseLogLevel(value)
+		if err != nil {
+			return LoggingClient{}, fmt.Errorf("%s: %s", envLogLevel, err.Error())
+		}
+		opts = append(opts, WithLevel(logLevel))
+	}
+
+	if value, ok := os.LookupEnv(envRemoteUrl); ok {
+		opts = append(opts, WithRemote(value))
+	}
+
+	if value, ok := os.LookupEnv(envLogFile); ok {
+		opts = append(opts, WithLogFile(value))
+	}
+
+	if value, ok := os.LookupEnv(envTimeout); ok {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return LoggingClient{}, fmt.Errorf("%s: %s", envTimeout, err.Error())
+		}
+		opts = append(opts, WithTimeout(timeout))
+	}
+
+	return New(owningServiceName, opts...)
+}
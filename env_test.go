@@ -0,0 +1,50 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+func TestLocalOnlyFromEnv(t *testing.T) {
+	t.Setenv(envLocalOnly, "true")
+
+	opts := []Option{
+		WithRemote("http://localhost:48061/api/v1/logs"),
+		WithNDJSONStream("http://localhost:9200/stream", time.Second),
+		WithElasticsearch("http://localhost:9200", "logs"),
+		WithSink(&recordingSink{}),
+		WithAlertWebhook("http://localhost:8080/alerts", support_domain.ERROR),
+	}
+	if runtime.GOOS != "windows" {
+		opts = append(opts, WithSyslog("udp", "localhost:514", "test"))
+	}
+	lc, err := New("test", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lc.Close()
+
+	if lc.remoteUrl() != "" || len(lc.sinks) != 0 || lc.syslog != nil {
+		t.Errorf("remote outputs set up when local only: %s", lc.Describe())
+	}
+}
@@ -0,0 +1,135 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Kinds of failure when sending to the logging service, to check for with errors.Is
+var (
+	// A log entry couldn't be encoded as JSON
+	ErrMarshal = errors.New("can't encode log entry")
+	// The request couldn't be made or got no answer, like when the logging service is unreachable.
+	// A RemoteStatusError is also an ErrSendFailed.
+	ErrSendFailed = errors.New("sending to the logging service failed")
+	// The send was discarded because the queue of sends was full
+	ErrDropped = errors.New("send dropped, the send queue is full")
+	// The send was refused because the client was closed
+	ErrClosed = errors.New("logging client is closed")
+)
+
+// Most of the response body kept in a RemoteStatusError
+const maxErrorBody = 512
+
+// The logging service answered with something other than a 2xx status. Get it with errors.As.
+type RemoteStatusError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	// The start of the response body, which usually explains the failure
+	Body string
+}
+
+func (e *RemoteStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("logging service at %s returned %s", e.URL, e.Status)
+	}
+	return fmt.Sprintf("logging service at %s returned %s: %s", e.URL, e.Status, e.Body)
+}
+
+func (e *RemoteStatusError) Unwrap() error {
+	return ErrSendFailed
+}
+
+// Whether the request was rejected as bad (a 4xx), so sending it again would fail the same way
+func (e *RemoteStatusError) Rejected() bool {
+	return e.StatusCode >= 400 && e.StatusCode <= 499
+}
+
+// A request that failed without a response, keeping the cause (which names the url) for errors.As
+type sendError struct {
+	err error
+}
+
+func (e *sendError) Error() string {
+	return ErrSendFailed.Error() + ": " + e.err.Error()
+}
+
+func (e *sendError) Unwrap() error {
+	return e.err
+}
+
+func (e *sendError) Is(target error) bool {
+	return target == ErrSendFailed
+}
+
+// Failures of asynchronous sends since the last success, so an outage is printed once
+// rather than on every log. Shared by every copy of the client.
+type outage struct {
+	mutex      sync.Mutex
+	failing    bool
+	lastError  string
+	suppressed int
+}
+
+// Record a failed send, returning whether it is worth printing: the first failure,
+// or one that fails differently from the last
+func (o *outage) failed(err error) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.failing && err.Error() == o.lastError {
+		o.suppressed++
+		return false
+	}
+	o.failing = true
+	o.lastError = err.Error()
+	return true
+}
+
+// Record a successful send. Returns whether it ended an outage, and how many failed sends
+// of the outage weren't reported.
+func (o *outage) recovered() (bool, int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.failing {
+		return false, 0
+	}
+	suppressed := o.suppressed
+	o.failing = false
+	o.lastError = ""
+	o.suppressed = 0
+	return true, suppressed
+}
+
+// Whether the logging service found the request too large (413 Payload Too Large)
+func isTooLarge(err error) bool {
+	var statusErr *RemoteStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusRequestEntityTooLarge
+}
+
+// Whether the request was rejected as bad, so sending it again would fail the same way
+func isRejected(err error) bool {
+	var statusErr *RemoteStatusError
+	return errors.As(err, &statusErr) && statusErr.Rejected()
+}
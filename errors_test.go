@@ -0,0 +1,151 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRemoteStatusError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unknown origin service", http.StatusBadRequest)
+	}))
+	defer server.Close()
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithSynchronous())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Info("message")
+	var statusErr *RemoteStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("got %v, want a RemoteStatusError", err)
+	}
+	if statusErr.StatusCode != http.StatusBadRequest || statusErr.Body != "unknown origin service" || statusErr.URL != server.URL || !statusErr.Rejected() {
+		t.Errorf("got %+v", statusErr)
+	}
+	if !errors.Is(err, ErrSendFailed) || errors.Is(err, ErrMarshal) {
+		t.Errorf("%v should be ErrSendFailed only", err)
+	}
+}
+
+func TestUnreachableError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithSynchronous())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.Info("message")
+	var statusErr *RemoteStatusError
+	if !errors.Is(err, ErrSendFailed) || errors.As(err, &statusErr) {
+		t.Fatalf("got %v, want ErrSendFailed without a status", err)
+	}
+	if !strings.Contains(err.Error(), server.URL) {
+		t.Errorf("%v doesn't name the url", err)
+	}
+}
+
+func TestMarshalError(t *testing.T) {
+	lc, err := New("test", WithWriter(io.Discard), WithRemote("http://localhost:48061/api/v1/logs"), WithSynchronous())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = lc.WithField("channel", make(chan int)).Info("message")
+	if !errors.Is(err, ErrMarshal) || errors.Is(err, ErrSendFailed) {
+		t.Fatalf("got %v, want ErrMarshal", err)
+	}
+}
+
+func TestRecoveryPrintedWithLogs(t *testing.T) {
+	var failures int32 = 3
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithRemote(server.URL), WithTimeFormat(""),
+		WithSendQueue(10, 1, BlockWhenFull))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 4; i++ {
+		lc.Info("message")
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// The failure and the recovery go with the logs, not straight to the process's stdout
+	got := stdout.String()
+	failure := strings.Index(got, "logging service at "+server.URL+" returned 500 Internal Server Error\n")
+	recovery := strings.Index(got, "remote logging recovered (2 more failed sends were not reported)\n")
+	if failure < 0 || recovery < failure || strings.Count(got, "INFO: message\n") != 4 {
+		t.Errorf("stdout got %q", got)
+	}
+}
+
+func TestErrorHandlerGetsLocalFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	// The backup can't replace a directory with something in it, so rotating fails
+	if err := os.MkdirAll(filepath.Join(path+".1", "taken"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var mutex sync.Mutex
+	var rotateFailed, marshalFailed bool
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithLogFile(path), WithFileRotation(1, 1),
+		WithRemote("http://localhost:48061/api/v1/logs"),
+		WithErrorHandler(func(err error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			rotateFailed = rotateFailed || strings.Contains(err.Error(), "rotating log file")
+			marshalFailed = marshalFailed || errors.Is(err, ErrMarshal)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc.Info("first")
+	lc.Info("second")
+	lc.WithField("channel", make(chan int)).Info("third")
+	lc.Close()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if !rotateFailed || !marshalFailed {
+		t.Errorf("error handler got rotation failure %t, encoding failure %t", rotateFailed, marshalFailed)
+	}
+	if strings.Contains(stdout.String(), "rotating") {
+		t.Errorf("failure printed as well: %q", stdout.String())
+	}
+}
@@ -0,0 +1,111 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Keys of a log entry as sent to the logging service, which WithFieldNames can rename
+var entryKeys = []string{
+	"logLevel", "originService", "message", "labels", "created", "fields",
+	"correlationId", "hostname", "pid", "stack", "schemaVersion",
+}
+
+// Rename the keys of entries sent to the logging service, like {"logLevel": "severity",
+// "created": "@timestamp"}, for ingestion endpoints that expect other names. The keys that
+// can be renamed are logLevel, originService, message, labels, created, fields, correlationId,
+// hostname, pid, stack and schemaVersion. Two keys can't end up with the same name.
+func WithFieldNames(names map[string]string) Option {
+	return func(lc *LoggingClient) error {
+		for key := range names {
+			if !containsLabel(entryKeys, key) {
+				return fmt.Errorf("unknown log entry key: %s", key)
+			}
+		}
+
+		renamed := make(map[string]bool, len(entryKeys))
+		for _, key := range entryKeys {
+			name := key
+			if to, ok := names[key]; ok {
+				name = to
+			}
+			if name == "" {
+				return fmt.Errorf("log entry key %s can't be renamed to nothing", key)
+			}
+			if renamed[name] {
+				return fmt.Errorf("more than one log entry key would be named %s", name)
+			}
+			renamed[name] = true
+		}
+
+		lc.fieldNames = names
+		return nil
+	}
+}
+
+// Encode the entry or batch of entries for the logging service, with the keys renamed by WithFieldNames
+func (lc LoggingClient) marshalPayload(payload interface{}) ([]byte, error) {
+	if len(lc.fieldNames) == 0 {
+		return json.Marshal(payload)
+	}
+
+	switch payload := payload.(type) {
+	case LogEntry:
+		return lc.marshalRenamed(payload)
+	case []LogEntry:
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, entry := range payload {
+			data, err := lc.marshalRenamed(entry)
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			buf.Write(data)
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), nil
+	}
+	return json.Marshal(payload)
+}
+
+// Encode the entry through a map, so its keys can be renamed without touching the LogEntry types
+func (lc LoggingClient) marshalRenamed(entry LogEntry) ([]byte, error) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var properties map[string]json.RawMessage
+	if err := json.Unmarshal(data, &properties); err != nil {
+		return nil, err
+	}
+
+	renamed := make(map[string]json.RawMessage, len(properties))
+	for key, value := range properties {
+		if name, ok := lc.fieldNames[key]; ok {
+			key = name
+		}
+		renamed[key] = value
+	}
+	return json.Marshal(renamed)
+}
@@ -0,0 +1,245 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bufio"
+	"github.com/edgexfoundry/support-domain-go"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Extra log file only receiving logs at or above minLevel, as lines or as CSV rows
+type fileSink struct {
+	path     string
+	minLevel support_domain.LogLevel
+	file     *logFile
+	csv      bool
+}
+
+// Files added with AddFileSink, shared by every copy of the client and guarded by its mutex
+type fileSinks struct {
+	sinks []*fileSink
+
+	// Number of sinks, read without the mutex to skip formatting lines no file wants
+	count int32
+}
+
+// Add a file. Callers hold the mutex, or the client isn't in use yet.
+func (fs *fileSinks) add(sink *fileSink) {
+	fs.sinks = append(fs.sinks, sink)
+	atomic.AddInt32(&fs.count, 1)
+}
+
+func (fs *fileSinks) hasSinks() bool {
+	return atomic.LoadInt32(&fs.count) > 0
+}
+
+// Placeholder in the log file path for the date of time based rotation
+const datePlaceholder = "{date}"
+
+// Log file that is kept open between writes
+type logFile struct {
+	path string
+	file *os.File
+	size int64
+
+	// Writes are buffered when bufferSize is set
+	bufferSize int
+	buffer     *bufio.Writer
+	flushTimer *time.Timer
+
+	// Set once a failure to open the file was reported, until it opens again
+	openFailed bool
+
+	// Written at the start of every new file, like the header row of a CSV file
+	header string
+}
+
+// Make sure the file at path is open, reopening if the path changed since the last write
+func (lf *logFile) open(path string) error {
+	if lf.file != nil && lf.path == path {
+		return nil
+	}
+
+	// The path changed (or nothing is open yet), release the old handle first
+	if err := lf.close(); err != nil {
+		return err
+	}
+
+	// The directory may not exist yet, or have been removed since the client was created
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	// Appending to an existing file counts towards its size
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	lf.path = path
+	lf.file = file
+	lf.size = info.Size()
+	if lf.bufferSize > 0 {
+		lf.buffer = bufio.NewWriterSize(file, lf.bufferSize)
+	}
+
+	if lf.size == 0 && lf.header != "" {
+		if _, err := lf.Write([]byte(lf.header)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Switch to the file at path, only closing the current file once the new one is open
+func (lf *logFile) reopen(path string) error {
+	next := logFile{bufferSize: lf.bufferSize, header: lf.header}
+	if err := next.open(path); err != nil {
+		return err
+	}
+
+	// The old handle is done with either way
+	lf.close()
+	*lf = next
+	return nil
+}
+
+// Write to the open file, keeping track of its size
+func (lf *logFile) Write(p []byte) (int, error) {
+	if lf.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	var n int
+	var err error
+	if lf.buffer != nil {
+		n, err = lf.buffer.Write(p)
+	} else {
+		n, err = lf.file.Write(p)
+	}
+	lf.size += int64(n)
+	return n, err
+}
+
+// Write out any buffered lines
+func (lf *logFile) flush() error {
+	if lf.buffer == nil {
+		return nil
+	}
+	return lf.buffer.Flush()
+}
+
+// Make sure buffered lines are written out within interval, calling flush to do it
+func (lf *logFile) scheduleFlush(interval time.Duration, flush func()) {
+	if lf.buffer == nil || interval <= 0 || lf.flushTimer != nil {
+		return
+	}
+	lf.flushTimer = time.AfterFunc(interval, flush)
+}
+
+// Move the current file to <path>.1, shifting older backups along and discarding
+// any beyond maxBackups, then start a fresh file at the same path
+func (lf *logFile) rotate(maxBackups int) error {
+	path := lf.path
+	if err := lf.close(); err != nil {
+		return err
+	}
+
+	var err error
+	if maxBackups < 1 {
+		err = os.Remove(path)
+	} else {
+		// Backups that don't exist yet are fine, so errors are ignored while shifting
+		os.Remove(backupPath(path, maxBackups))
+		for i := maxBackups - 1; i >= 1; i-- {
+			os.Rename(backupPath(path, i), backupPath(path, i+1))
+		}
+		err = os.Rename(path, backupPath(path, 1))
+	}
+
+	// Keep logging even if the old file couldn't be moved out of the way
+	if openErr := lf.open(path); openErr != nil {
+		return openErr
+	}
+	return err
+}
+
+// Use the separators of the OS in a log file path, so a path like C:/logs/app.log
+// (as often written in configuration) works on Windows like C:\logs\app.log
+func cleanPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	return filepath.Clean(filepath.FromSlash(path))
+}
+
+// Path of the log file for the current period when rotating by time: the date
+// formatted with layout replaces the placeholder, or goes before the extension
+// if there is no placeholder. Paths are left alone when layout is empty.
+func datedPath(path string, layout string, now time.Time) string {
+	if layout == "" {
+		return path
+	}
+
+	date := now.Format(layout)
+	if strings.Contains(path, datePlaceholder) {
+		return strings.Replace(path, datePlaceholder, date, -1)
+	}
+
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + date + ext
+}
+
+// Path of the nth most recent backup of the log file
+func backupPath(path string, n int) string {
+	return path + "." + strconv.Itoa(n)
+}
+
+// Close the currently open file, if any
+func (lf *logFile) close() error {
+	if lf.file == nil {
+		return nil
+	}
+
+	if lf.flushTimer != nil {
+		lf.flushTimer.Stop()
+		lf.flushTimer = nil
+	}
+
+	err := lf.flush()
+	if closeErr := lf.file.Close(); err == nil {
+		err = closeErr
+	}
+	lf.file = nil
+	lf.buffer = nil
+	lf.path = ""
+	lf.size = 0
+	return err
+}
@@ -0,0 +1,210 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestRotateBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	// Lines like "INFO: line 0\n" are 13 bytes, so every file takes two
+	lc, err := New("test", WithoutConsole(), WithTimeFormat(""), WithLogFile(path), WithFileRotation(26, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		lc.Info(fmt.Sprintf("line %d", i))
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		path:        "INFO: line 8\nINFO: line 9\n",
+		path + ".1": "INFO: line 6\nINFO: line 7\n",
+		path + ".2": "INFO: line 4\nINFO: line 5\n",
+	}
+	for file, want := range files {
+		if got := readFile(t, file); got != want {
+			t.Errorf("%s holds %q, want %q", filepath.Base(file), got, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 kept beyond MaxBackups", filepath.Base(path))
+	}
+}
+
+// Number of file descriptors open on path, where the platform can tell
+func openFiles(t *testing.T, path string) int {
+	t.Helper()
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("can't list open files on this platform")
+	}
+	open := 0
+	for _, fd := range fds {
+		if target, err := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); err == nil && target == path {
+			open++
+		}
+	}
+	return open
+}
+
+func TestLogFileKeptOpen(t *testing.T) {
+	// Open files are listed by their real path
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "app.log")
+	lc, err := New("test", WithoutConsole(), WithLogFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10000; i++ {
+		lc.Info("message")
+	}
+	if open := openFiles(t, path); open != 1 {
+		t.Errorf("log file open %d times while logging, want once", open)
+	}
+
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if open := openFiles(t, path); open != 0 {
+		t.Errorf("log file open %d times after Close", open)
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	type pathTest struct {
+		path string
+		want string
+		dir  string
+	}
+	tests := []pathTest{
+		{"", "", ""},
+		{"app.log", "app.log", "."},
+		{"/var/log/app.log", "/var/log/app.log", "/var/log"},
+		{"logs//app.log", "logs/app.log", "logs"},
+		{"./logs/../app.log", "app.log", "."},
+		// A backslash is an ordinary character in Unix file names
+		{`C:\logs\app.log`, `C:\logs\app.log`, "."},
+	}
+	if runtime.GOOS == "windows" {
+		tests = []pathTest{
+			{"", "", ""},
+			{"app.log", "app.log", "."},
+			{`C:\logs\app.log`, `C:\logs\app.log`, `C:\logs`},
+			{"C:/logs/app.log", `C:\logs\app.log`, `C:\logs`},
+			{`C:\logs\\edgex/app.log`, `C:\logs\edgex\app.log`, `C:\logs\edgex`},
+			{`\\server\share\app.log`, `\\server\share\app.log`, `\\server\share\`},
+			{"logs/app.log", `logs\app.log`, "logs"},
+		}
+	}
+
+	for _, test := range tests {
+		got := cleanPath(test.path)
+		if got != test.want {
+			t.Errorf("cleanPath(%q) = %q, want %q", test.path, got, test.want)
+		}
+		if got != "" && filepath.Dir(got) != test.dir {
+			t.Errorf("directory of %q is %q, want %q", test.path, filepath.Dir(got), test.dir)
+		}
+	}
+}
+
+func TestCSVFileOpenedOnFirstRow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logs", "app.csv")
+	lc, err := New("test", WithWriter(io.Discard), WithCSV(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("csv file created before logging: %v", err)
+	}
+
+	lc.Info("started")
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rows := strings.Split(strings.TrimSpace(readFile(t, path)), "\n"); len(rows) != 2 || rows[0]+"\n" != csvHeader {
+		t.Errorf("csv file has rows %q", rows)
+	}
+
+	// A directory that can't be made is still reported by New
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New("test", WithCSV(filepath.Join(blocker, "app.csv"))); err == nil {
+		t.Error("csv file under a regular file accepted")
+	}
+}
+
+func TestUnwritableDirectoryFallsBackToStdout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("directory permissions don't stop writes on Windows")
+	}
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to a read-only directory")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0500); err != nil {
+		t.Fatal(err)
+	}
+	// Writable again so the directory can be removed
+	defer os.Chmod(dir, 0700)
+
+	var stdout strings.Builder
+	var reported []error
+	lc, err := New("test", WithWriter(&stdout), WithoutConsole(), WithLogFile(filepath.Join(dir, "app.log")),
+		WithLazyValidation(), WithErrorHandler(func(err error) { reported = append(reported, err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc.Info("first")
+	lc.Info("second")
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(stdout.String(), "first") || !strings.Contains(stdout.String(), "second") {
+		t.Errorf("logs not on stdout: %q", stdout.String())
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "logging to stdout instead") {
+		t.Errorf("reported %v, want the failure once", reported)
+	}
+}
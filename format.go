@@ -0,0 +1,229 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Cut the message down to at most size bytes, noting how much was cut
+func truncateMessage(msg string, size int) string {
+	cut := runeBoundary(msg, size)
+	return fmt.Sprintf("%s...[truncated %d bytes]", msg[:cut], len(msg)-cut)
+}
+
+// Split the message into pieces of at most size bytes
+func splitMessage(msg string, size int) []string {
+	var chunks []string
+	for len(msg) > size {
+		cut := runeBoundary(msg, size)
+		chunks = append(chunks, msg[:cut])
+		msg = msg[cut:]
+	}
+	return append(chunks, msg)
+}
+
+// The largest index up to n that doesn't fall inside a UTF-8 character.
+// Falls back to n if the message isn't valid UTF-8 there.
+func runeBoundary(msg string, n int) int {
+	for i := n; i > 0 && i > n-utf8.UTFMax; i-- {
+		if utf8.RuneStart(msg[i]) {
+			return i
+		}
+	}
+	return n
+}
+
+// Render the message of an entry for the local (stdout and file) logs,
+// followed by its fields as key=value pairs
+func formatMessage(entry LogEntry) string {
+	if len(entry.Fields) == 0 {
+		return entry.Message
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(entry.Message)
+	for _, key := range sortedKeys(entry.Fields) {
+		buf.WriteByte(' ')
+		buf.WriteString(logfmtValue(key))
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(fmt.Sprint(entry.Fields[key])))
+	}
+	return buf.String()
+}
+
+// Render an entry as a single line of JSON for the local logs, with the same
+// properties as sent to the logging service
+func formatJSON(entry LogEntry) string {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// Only the fields can hold values that can't be marshaled
+		entry.Fields = map[string]interface{}{"fieldsError": err.Error()}
+		line, _ = json.Marshal(entry)
+	}
+	return string(line)
+}
+
+// Render an entry as a logfmt line for the local logs, like
+// time=... level=info msg="device offline" service=edgex-core-data labels=a,b key=value
+func formatLogfmt(entry LogEntry, timestamp string) string {
+	var buf bytes.Buffer
+	writePair := func(key string, value string) {
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(logfmtKey(key))
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(value))
+	}
+
+	if timestamp != "" {
+		writePair("time", timestamp)
+	}
+	writePair("level", strings.ToLower(string(entry.Level)))
+	writePair("msg", entry.Message)
+	writePair("service", entry.OriginService)
+	if len(entry.Labels) > 0 {
+		writePair("labels", strings.Join(entry.Labels, ","))
+	}
+	if entry.CorrelationID != "" {
+		writePair("correlation_id", entry.CorrelationID)
+	}
+	for _, key := range sortedKeys(entry.Fields) {
+		writePair(key, fmt.Sprint(entry.Fields[key]))
+	}
+	if entry.Stack != "" {
+		writePair("stack", entry.Stack)
+	}
+	return buf.String()
+}
+
+// Keys can't be quoted, so replace whatever would break the key=value pair
+func logfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '=' || r == '"' || needsEscape(r) {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// Header row of CSV log files
+const csvHeader = "timestamp,level,service,message,labels\n"
+
+// Render an entry as a CSV row (without the line end) for CSV log files.
+// Labels are joined with spaces, the fields follow the message as on text lines.
+func formatCSV(entry LogEntry, timestamp string) string {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	writer.Write([]string{timestamp, string(entry.Level), entry.OriginService, formatMessage(entry), strings.Join(entry.Labels, " ")})
+	writer.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Keys of the fields in a stable order so lines are easy to compare
+func sortedKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Quote the value if it would otherwise be ambiguous in a key=value pair
+func logfmtValue(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"") || strings.IndexFunc(s, needsEscape) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// Replace line breaks and other control characters with escapes like \n and \u001b
+func escapeControl(s string) string {
+	if strings.IndexFunc(s, isControl) < 0 {
+		return s
+	}
+
+	var buf strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case isControl(r):
+			fmt.Fprintf(&buf, `\u%04x`, r)
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// Control characters, including the C1 controls and the Unicode line and paragraph separators
+func isControl(r rune) bool {
+	return needsEscape(r) || (r >= 0x80 && r <= 0x9f) || r == 0x2028 || r == 0x2029
+}
+
+func needsEscape(r rune) bool {
+	return r < ' ' || r == 0x7f
+}
+
+// ANSI color of each level on an interactive stdout
+var levelColors = map[support_domain.LogLevel]string{
+	support_domain.TRACE: "\x1b[90m",
+	support_domain.DEBUG: "\x1b[36m",
+	support_domain.INFO:  "\x1b[32m",
+	support_domain.WARN:  "\x1b[33m",
+	support_domain.ERROR: "\x1b[31m",
+}
+
+const colorReset = "\x1b[0m"
+
+// Color the level name at the start of a line prefix
+func colorPrefix(logLevel support_domain.LogLevel, prefix string) string {
+	color, ok := levelColors[logLevel]
+	if !ok || !strings.HasPrefix(prefix, string(logLevel)) {
+		return prefix
+	}
+	return color + string(logLevel) + colorReset + prefix[len(logLevel):]
+}
+
+// Check whether the file is a terminal rather than, say, a pipe to a log collector
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,132 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+// Renders log entries for stdout and the log file. Set one with WithFormatter for a layout
+// of your own, or use TextFormatter, JSONFormatter or LogfmtFormatter.
+// The line end is added by the client.
+type Formatter interface {
+	Format(entry LogEntry) ([]byte, error)
+}
+
+// Text lines like "INFO: 2006/01/02 15:04:05 main.go:42: message key=value", with control
+// characters in the message escaped unless RawMessages is set. An empty TimeFormat leaves the
+// timestamp out. With Caller set, lines at or above CallerLevel (every line if it is empty) have
+// the file and line that logged; it is looked up from the stack, so only while logging.
+type TextFormatter struct {
+	TimeFormat  string
+	Caller      bool
+	CallerLevel support_domain.LogLevel
+	RawMessages bool
+}
+
+func (f TextFormatter) Format(entry LogEntry) ([]byte, error) {
+	line := string(entry.Level) + ": "
+	if f.TimeFormat != "" {
+		line += entryTime(entry).Format(f.TimeFormat) + " "
+	}
+	// Finding the caller costs more than the rest of the line, so it is only done where it helps most
+	if f.Caller && atLeast(entry.Level, f.CallerLevel) {
+		line += callerLocation() + ": "
+	}
+	if f.RawMessages {
+		line += formatMessage(entry)
+	} else {
+		line += escapeControl(formatMessage(entry))
+	}
+	if entry.Stack != "" {
+		line += "\n" + entry.Stack
+	}
+	return []byte(line), nil
+}
+
+// One JSON object per line, with the same properties as sent to the logging service
+type JSONFormatter struct{}
+
+func (f JSONFormatter) Format(entry LogEntry) ([]byte, error) {
+	return []byte(formatJSON(entry)), nil
+}
+
+// logfmt lines like "time=... level=info msg=... service=... key=value".
+// An empty TimeFormat leaves the time out.
+type LogfmtFormatter struct {
+	TimeFormat string
+}
+
+func (f LogfmtFormatter) Format(entry LogEntry) ([]byte, error) {
+	var timestamp string
+	if f.TimeFormat != "" {
+		timestamp = entryTime(entry).Format(f.TimeFormat)
+	}
+	return []byte(formatLogfmt(entry, timestamp)), nil
+}
+
+// Render the entry with the formatter for a local output, falling back to
+// the message if the formatter fails
+func (lc LoggingClient) formatLocal(formatter Formatter, entry LogEntry, line string) string {
+	out, err := formatter.Format(entry)
+	if err != nil {
+		lc.reportError(err)
+		return line
+	}
+	// The loggers add the line end
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+// The built-in layout for outputs without a formatter of their own: JSON (WithJSONOutput),
+// logfmt (WithLogfmt) or text lines
+func (lc LoggingClient) defaultFormatter() Formatter {
+	switch {
+	case lc.jsonOutput:
+		return JSONFormatter{}
+	case lc.logfmtOutput:
+		return LogfmtFormatter{TimeFormat: lc.timeFormat}
+	default:
+		return TextFormatter{
+			TimeFormat:  lc.timeFormat,
+			Caller:      lc.includeCaller,
+			CallerLevel: lc.callerLevel,
+			RawMessages: lc.rawMessages,
+		}
+	}
+}
+
+// The formatter without the caller, for lines where the caller would be misleading
+func withoutCaller(formatter Formatter) Formatter {
+	if text, ok := formatter.(TextFormatter); ok {
+		text.Caller = false
+		return text
+	}
+	return formatter
+}
+
+// When the entry was created, in local time. Entries built by the client keep the
+// precise time, Created only has milliseconds.
+func entryTime(entry LogEntry) time.Time {
+	if !entry.time.IsZero() {
+		return entry.time
+	}
+	return time.Unix(0, entry.Created*int64(time.Millisecond))
+}
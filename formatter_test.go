@@ -0,0 +1,64 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestFormatterPerOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithLogFile(path), WithJSONOutput(),
+		WithStdOutFormatter(TextFormatter{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc.Info("started")
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := stdout.String(); got != "INFO: started\n" {
+		t.Errorf("stdout got %q", got)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal([]byte(readFile(t, path)), &entry); err != nil || entry.Message != "started" {
+		t.Errorf("file got %q, %v", readFile(t, path), err)
+	}
+}
+
+func TestTextFormatter(t *testing.T) {
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithFormatter(TextFormatter{TimeFormat: "15:04", Caller: true, RawMessages: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc.Info("first\nsecond")
+
+	// The formatter's line replaces the built-in one, nothing is added in front of it.
+	// The caller is outside this package, which the tests are part of.
+	line := stdout.String()
+	if !regexp.MustCompile(`^INFO: \d\d:\d\d \S+\.go:\d+: first\nsecond\n$`).MatchString(line) {
+		t.Errorf("line got %q", line)
+	}
+}
@@ -0,0 +1,263 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+// Package grpcsink streams log entries to a gRPC logging endpoint over one client streaming
+// call, for services logging too much for a POST per entry. Add it to a client with
+// logger.WithSink(grpcsink.New(target)).
+package grpcsink
+
+import (
+	"context"
+	"fmt"
+	"github.com/edgexfoundry/support-logging-client-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protowire"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client streaming RPC receiving log entries, defined in logging.proto
+const grpcMethod = "/edgex.logging.v1.LoggingService/StreamLogs"
+
+// Entries waiting for the stream; beyond this new entries are dropped rather than block logging
+const grpcBufferSize = 1000
+
+// Longest wait between attempts to open the stream again
+const grpcMaxBackoff = 30 * time.Second
+
+// How long Close waits for the buffered entries to be sent
+const closeTimeout = 10 * time.Second
+
+// Streams log entries to a gRPC logging endpoint over one long lived stream
+type Sink struct {
+	target      string
+	dialOptions []grpc.DialOption
+	entries     chan logger.LogEntry
+	done        chan struct{}
+	stopped     chan struct{}
+	start       sync.Once
+	stop        sync.Once
+	dropped     uint64
+	report      func(error)
+}
+
+// Create a sink streaming log entries to the gRPC logging endpoint at target (like
+// "localhost:48062"), with the StreamLogs call of logging.proto, for logger.WithSink.
+// Without dial options the connection is plaintext; pass grpc.WithTransportCredentials for
+// TLS. The stream is opened on the first log and again whenever it breaks. If it can't keep
+// up, entries are dropped and counted by Dropped.
+func New(target string, opts ...grpc.DialOption) *Sink {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	return &Sink{
+		target:      target,
+		dialOptions: opts,
+		entries:     make(chan logger.LogEntry, grpcBufferSize),
+		done:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+		report: func(err error) {
+			fmt.Println(err.Error())
+		},
+	}
+}
+
+// Report failures of the stream with report instead of printing them, called by the client
+func (s *Sink) SetErrorReporter(report func(error)) {
+	s.report = report
+}
+
+func (s *Sink) Send(entry logger.LogEntry) error {
+	s.start.Do(func() {
+		go s.run()
+	})
+
+	select {
+	case s.entries <- entry:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+	return nil
+}
+
+// Number of entries dropped because the stream couldn't keep up
+func (s *Sink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Sink) String() string {
+	return "grpc=" + s.target
+}
+
+// Send what is still buffered and end the stream, waiting (for a bounded time) for it to finish
+func (s *Sink) Close() error {
+	// Nothing to wait for if nothing was ever logged
+	s.start.Do(func() {
+		close(s.stopped)
+	})
+	s.stop.Do(func() {
+		close(s.done)
+	})
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("timed out closing the gRPC log stream to %s", s.target)
+	}
+}
+
+// Keep a stream open for as long as the client is, opening it again when it breaks
+func (s *Sink) run() {
+	defer close(s.stopped)
+
+	// Doesn't wait for the connection, which gRPC re-establishes by itself when it drops
+	conn, err := grpc.Dial(s.target, s.dialOptions...)
+	if err != nil {
+		s.report(fmt.Errorf("can't connect to gRPC logging endpoint %s: %s", s.target, err.Error()))
+		return
+	}
+	defer conn.Close()
+
+	var pending *logger.LogEntry
+	backoff := time.Second
+	for {
+		stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{StreamName: "StreamLogs", ClientStreams: true}, grpcMethod, grpc.ForceCodec(rawCodec{}))
+		if err == nil {
+			backoff = time.Second
+			if pending, err = s.stream(stream, pending); err == nil {
+				return
+			}
+		}
+		s.report(fmt.Errorf("gRPC log stream to %s failed: %s", s.target, err.Error()))
+
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			return
+		}
+		if backoff *= 2; backoff > grpcMaxBackoff {
+			backoff = grpcMaxBackoff
+		}
+	}
+}
+
+// Send entries on the stream until the sink is closed. Returns the entry being sent if the
+// stream breaks, to send first on the next stream.
+func (s *Sink) stream(stream grpc.ClientStream, pending *logger.LogEntry) (*logger.LogEntry, error) {
+	if pending != nil {
+		if err := stream.SendMsg(encodeGRPCEntry(*pending)); err != nil {
+			return pending, err
+		}
+	}
+
+	for {
+		select {
+		case entry := <-s.entries:
+			if err := stream.SendMsg(encodeGRPCEntry(entry)); err != nil {
+				return &entry, err
+			}
+		case <-s.done:
+			// Send what is still buffered, then end the stream
+			for len(s.entries) > 0 {
+				entry := <-s.entries
+				if err := stream.SendMsg(encodeGRPCEntry(entry)); err != nil {
+					return &entry, err
+				}
+			}
+
+			if err := stream.CloseSend(); err != nil {
+				return nil, err
+			}
+			var response []byte
+			return nil, stream.RecvMsg(&response)
+		}
+	}
+}
+
+// Passes messages encoded by encodeGRPCEntry through as they are
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("unexpected gRPC message type %T", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("unexpected gRPC message type %T", v)
+	}
+	*b = data
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// Encode an entry as the LogEntry message of logging.proto. Field values are sent as text.
+func encodeGRPCEntry(entry logger.LogEntry) []byte {
+	var b []byte
+	b = appendProtoString(b, 1, string(entry.Level))
+	for _, label := range entry.Labels {
+		b = protowire.AppendTag(b, 2, protowire.BytesType)
+		b = protowire.AppendString(b, label)
+	}
+	b = appendProtoString(b, 3, entry.OriginService)
+	b = appendProtoString(b, 4, entry.Message)
+	b = appendProtoVarint(b, 5, uint64(entry.Created))
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		var field []byte
+		field = appendProtoString(field, 1, key)
+		field = appendProtoString(field, 2, fmt.Sprint(entry.Fields[key]))
+		b = protowire.AppendTag(b, 6, protowire.BytesType)
+		b = protowire.AppendBytes(b, field)
+	}
+	b = appendProtoString(b, 7, entry.CorrelationID)
+	b = appendProtoString(b, 8, entry.Hostname)
+	b = appendProtoVarint(b, 9, uint64(entry.Pid))
+	return b
+}
+
+// Like proto3, leave out fields with their default value
+func appendProtoString(b []byte, num protowire.Number, s string) []byte {
+	if s == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, s)
+}
+
+func appendProtoVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"sync"
+)
+
+// Custom logic run on every log entry, like counting errors or raising an alert
+type Hook interface {
+	// Called with each entry that passes the log level. An error is reported
+	// through the error handler and doesn't stop the entry being logged.
+	// The labels and fields may be shared with other entries, so don't modify them.
+	Fire(entry LogEntry) error
+}
+
+// Hooks added with AddHook, shared by every copy of the client
+type hooks struct {
+	mutex sync.RWMutex
+	hooks []Hook
+}
+
+// Run the hook on every log entry from now on, before it is written anywhere
+func (lc LoggingClient) AddHook(h Hook) {
+	lc = lc.initialized()
+	lc.hooks.mutex.Lock()
+	defer lc.hooks.mutex.Unlock()
+
+	lc.hooks.hooks = append(lc.hooks.hooks, h)
+}
+
+// Run the hooks outside the lock, so a hook may log or add hooks itself
+func (lc LoggingClient) fireHooks(entry LogEntry) {
+	lc.hooks.mutex.RLock()
+	hooks := lc.hooks.hooks
+	lc.hooks.mutex.RUnlock()
+
+	for _, h := range hooks {
+		if err := h.Fire(entry); err != nil {
+			lc.reportError(err)
+		}
+	}
+}
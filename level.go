@@ -0,0 +1,55 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"strings"
+)
+
+// Ordering of the log levels, lowest severity first
+var logLevels = map[support_domain.LogLevel]int{
+	support_domain.TRACE: 0,
+	support_domain.DEBUG: 1,
+	support_domain.INFO:  2,
+	support_domain.WARN:  3,
+	support_domain.ERROR: 4,
+}
+
+// Get the log level named by s, ignoring case and surrounding whitespace
+func ParseLogLevel(s string) (support_domain.LogLevel, error) {
+	logLevel := support_domain.LogLevel(strings.ToUpper(strings.TrimSpace(s)))
+	if _, ok := logLevels[logLevel]; !ok {
+		return "", fmt.Errorf("unknown log level: %q", s)
+	}
+	return logLevel, nil
+}
+
+// Get the name of the log level, as accepted by ParseLogLevel, or UNKNOWN if it isn't one
+func LogLevelString(logLevel support_domain.LogLevel) string {
+	if _, ok := logLevels[logLevel]; !ok {
+		return "UNKNOWN"
+	}
+	return string(logLevel)
+}
+
+// Whether the level is at or above minLevel. An empty minLevel lets everything through.
+func atLeast(logLevel support_domain.LogLevel, minLevel support_domain.LogLevel) bool {
+	return minLevel == "" || logLevels[logLevel] >= logLevels[minLevel]
+}
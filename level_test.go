@@ -0,0 +1,52 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package logger
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		input string
+		want  support_domain.LogLevel
+		valid bool
+	}{
+		{"WARN", support_domain.WARN, true},
+		{" Info ", support_domain.INFO, true},
+		{"wArN", support_domain.WARN, true},
+		{"\tdebug\n", support_domain.DEBUG, true},
+		{"verbose", "", false},
+		{"", "", false},
+	}
+	for _, test := range tests {
+		got, err := ParseLogLevel(test.input)
+		if (err == nil) != test.valid || got != test.want {
+			t.Errorf("ParseLogLevel(%q) = %q, %v, want %q", test.input, got, err, test.want)
+		}
+		if test.valid && LogLevelString(got) != string(test.want) {
+			t.Errorf("LogLevelString(%q) = %q", got, LogLevelString(got))
+		}
+	}
+	if got := LogLevelString("verbose"); got != "UNKNOWN" {
+		t.Errorf("LogLevelString of an unknown level = %q", got)
+	}
+}
@@ -20,145 +20,1435 @@ package logger
 // Logging client for the Go implementation of edgexfoundry
 
 import (
+	"bufio"
 	"bytes"
-	"encoding/json"
+	"compress/gzip"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/edgexfoundry/support-domain-go"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type LoggingClient struct {
 	owningServiceName string
+	// Where logs go as the client was created. SetRemoteURL and SetLogFile switch every
+	// copy of the client to other targets without changing these.
 	RemoteUrl         string
 	LogFilePath       string
+	EnableStdOut      bool
+	RemoteTimeout     time.Duration
+	MaxRetries        int
+	RetryBaseDelay    time.Duration
+	BatchSize         int
+	FlushInterval     time.Duration
+	RemoteHeaders     http.Header
+	Synchronous       bool
+	MaxFileSize       int64
+	MaxBackups        int
+	RotateTimeLayout  string
+	MaxMessageSize    int
+	SplitMessages     bool
+	SplitOnTooLarge   bool
 	stdOutLogger      *log.Logger
+	stdErrLogger      *log.Logger
 	fileLogger        *log.Logger
+	logFile           *logFile
+	mutex             *sync.Mutex
+	httpClient        *http.Client
+	callbacks         *callbacks
+	batch             *logBatch
+	sends             *sendTracker
+	discard           bool
+	fields            map[string]interface{}
+	correlationIDKey  interface{}
+	contextFields     []func(context.Context) map[string]interface{}
+	baseCorrelationID string
+	authorize         func(*http.Request)
+	syslog            syslogSink
+	exitCode          int
+	jsonOutput        bool
+	logfmtOutput      bool
+	stdOutFormatter   Formatter
+	fileFormatter     Formatter
+	queryKeys         []string
+	localOnly         bool
+	fieldNames        map[string]string
+	outage            *outage
+	stdOutBufferSize  int
+	stdOutBuffer      *stdOutBuffer
+	allLevels         bool
+	timeFormat        string
+	includeCaller     bool
+	callerLevel       support_domain.LogLevel
+	color             bool
+	stats             *sendStats
+	queue             *sendQueue
+	compress          bool
+	targets           *targets
+	fileFlushInterval time.Duration
+	fileSinks         *fileSinks
+	sinks             []entrySink
+	sampler           *sampler
+	hooks             *hooks
+	redactor          *redactor
+	labels            []string
+	lazyValidation    bool
+	hostname          string
+	breaker           *circuitBreaker
+	spool             *spool
+	stdOutLevel       support_domain.LogLevel
+	fileLevel         support_domain.LogLevel
+	remoteLevel       support_domain.LogLevel
+	clock             Clock
+	signalFlush       bool
+	signalWatch       *signalWatch
+	rawMessages       bool
+	deduplicator      *deduplicator
+	remoteInclude     []string
+	remoteExclude     []string
+	stackLevel        support_domain.LogLevel
+	maxAlerts         int
+	alertInterval     time.Duration
 }
 
-// Create a new logging client for the owning service
+// Settings changed at runtime by SetLogLevel, SetRemoteURL and SetLogFile, seen by every copy of the client
+type targets struct {
+	logLevel    atomic.Value
+	remoteUrl   atomic.Value
+	logFilePath atomic.Value
+
+	// Guards changes of the level, so a temporary level is only reverted if nothing else changed it
+	levelMutex sync.Mutex
+	levelTimer *time.Timer
+	levelGen   uint64
+	restore    support_domain.LogLevel
+}
+
+// Output for logs that knows how to represent each level, implemented by syslog
+type syslogSink interface {
+	write(logLevel support_domain.LogLevel, msg string) error
+	close() error
+}
+
+// Additional output receiving every log entry, like the OTLP output or a Sink.
+// Given the client logging the entry (or being closed), for its settings.
+type entrySink interface {
+	send(lc LoggingClient, entry LogEntry) error
+	close(lc LoggingClient) error
+}
+
+// Add an output set up by an option, unless logging is local only. Sinks connect on their
+// first entry, so one left out has nothing to close.
+func (lc *LoggingClient) addSink(sink entrySink) {
+	if !lc.localOnly {
+		lc.sinks = append(lc.sinks, sink)
+	}
+}
+
+// Version of the entry format, so the logging service can tell what a client sends.
+// Entries without a version are the plain support-domain entry, version 1; version 2 adds the
+// fields of LogEntry. Bump it, in step with support-domain, whenever the format changes.
+const SchemaVersion = 2
+
+// Log entry sent to the logging service. Extends the support-domain entry with the
+// fields filled in by this client, so it serializes to a superset of the same JSON.
+type LogEntry struct {
+	support_domain.LogEntry
+	Created int64                  `json:"created"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+
+	// Identifies the request the entry was logged for, when logged with a context
+	CorrelationID string `json:"correlationId,omitempty"`
+
+	// Identify the instance of the service that logged the entry
+	Hostname string `json:"hostname,omitempty"`
+	Pid      int    `json:"pid,omitempty"`
+
+	// Where the entry was logged from, for levels configured with WithStackTrace
+	Stack string `json:"stack,omitempty"`
+
+	// Format of the entry, set to SchemaVersion
+	SchemaVersion int `json:"schemaVersion"`
+
+	// Created to the nanosecond, for local timestamps
+	time time.Time
+}
+
+// Default time allowed for a request to the logging service
+const defaultRemoteTimeout = 5 * time.Second
+
+// Default layout of the timestamp on local log lines, as the standard logger prints it
+const defaultTimeFormat = "2006/01/02 15:04:05"
+
+// Idle connections kept per host, enough for the send workers to all reuse a connection.
+// The standard transport only keeps 2, so concurrent sends keep opening new connections.
+const maxIdleConnsPerHost = 16
+
+// Payloads smaller than this aren't worth compressing
+const compressionThreshold = 1024
+
+// How long Close waits for sends that are still in flight
+const closeTimeout = 10 * time.Second
+
+// Default limit on the size of a message, well above any normal log but below the body
+// size limit of the logging service
+const defaultMaxMessageSize = 256 * 1024
+
+// Create a new logging client for the owning service. The url isn't checked,
+// use New with WithRemote to have a bad url reported.
 func NewClient(owningServiceName string, remoteUrl string) LoggingClient {
+	// Nothing else can fail, so neither can New
+	lc, _ := New(owningServiceName, WithRemote(remoteUrl), WithLazyValidation())
+	return lc
+}
+
+// Create a logging client that discards everything, for use in tests and benchmarks
+func NewNullClient() LoggingClient {
+	lc, _ := New("", WithDiscard())
+	return lc
+}
+
+// Create a new logging client for the owning service, configured by the given options
+func New(owningServiceName string, opts ...Option) (LoggingClient, error) {
 	// Set up logging client
 	lc := LoggingClient{
 		owningServiceName: owningServiceName,
-		RemoteUrl:         remoteUrl,
+		EnableStdOut:      true,
+		RemoteTimeout:     defaultRemoteTimeout,
+		correlationIDKey:  CorrelationIDKey,
+		exitCode:          1,
+		timeFormat:        defaultTimeFormat,
+		includeCaller:     true,
+		callerLevel:       support_domain.WARN,
+		MaxMessageSize:    defaultMaxMessageSize,
+		SplitOnTooLarge:   true,
+		clock:             realClock{},
+		maxAlerts:         defaultMaxAlerts,
+		alertInterval:     defaultAlertInterval,
 	}
 
 	// Set up the loggers
-	lc.stdOutLogger = log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lshortfile)
+	lc.stdOutLogger = log.New(os.Stdout, "", 0)
 	lc.fileLogger = &log.Logger{}
-	lc.fileLogger.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
+	lc.logFile = &logFile{}
+
+	// Shared by every copy of the client, since the loggers' prefixes are shared state
+	lc.mutex = &sync.Mutex{}
+
+	// The http client is safe for concurrent use and pools connections, so share it
+	lc.httpClient = &http.Client{Transport: newTransport()}
+	lc.batch = &logBatch{}
+	lc.sends = &sendTracker{}
+	lc.stats = &sendStats{}
+	lc.targets = &targets{}
+	// Converted since the level constants of support-domain may be untyped
+	lc.targets.logLevel.Store(support_domain.LogLevel(support_domain.TRACE))
+	lc.fileSinks = &fileSinks{}
+	lc.hooks = &hooks{}
+	lc.callbacks = &callbacks{}
+	lc.queue = newSendQueue(defaultQueueSize, defaultSendWorkers, DropNewest, lc.stats)
+	lc.outage = &outage{}
 
 	// Default path
 	lc.LogFilePath = ""
 
-	return lc
+	// Resolved once rather than on every log. Without a hostname the pid still helps.
+	lc.hostname, _ = os.Hostname()
+
+	// Nothing leaves the machine, stdout and the log files still get every entry. Known before
+	// the options run, so remote transports are never set up.
+	lc.localOnly = localOnlyFromEnv()
+
+	for _, opt := range opts {
+		if err := opt(&lc); err != nil {
+			return LoggingClient{}, err
+		}
+	}
+
+	lc.LogFilePath = cleanPath(lc.LogFilePath)
+
+	// Sinks report to the client as finally configured
+	for _, sink := range lc.sinks {
+		if custom, ok := sink.(customSink); ok {
+			if reporting, ok := custom.Sink.(ErrorReportingSink); ok {
+				reporting.SetErrorReporter(lc.reportError)
+			}
+		}
+	}
+
+	// Outputs without a formatter of their own get the built-in layout, resolved once all options
+	// ran so the order of the options doesn't matter
+	if lc.stdOutFormatter == nil {
+		lc.stdOutFormatter = lc.defaultFormatter()
+	}
+	if lc.fileFormatter == nil {
+		lc.fileFormatter = lc.defaultFormatter()
+	}
+
+	// Colors only make sense on a terminal, not a file or pipe or other writer
+	if lc.color {
+		file, ok := lc.stdOutLogger.Writer().(*os.File)
+		lc.color = ok && isTerminal(file)
+	}
+
+	if lc.stdOutBufferSize > 0 {
+		lc.stdOutBuffer = &stdOutBuffer{writer: bufio.NewWriterSize(lc.stdOutLogger.Writer(), lc.stdOutBufferSize)}
+		lc.stdOutLogger = log.New(lc.stdOutBuffer.writer, "", 0)
+	}
+
+	if !lc.lazyValidation {
+		if err := lc.validate(); err != nil {
+			return LoggingClient{}, err
+		}
+	}
+
+	// Send what was left in the spool by a previous run, in case the logging service is reachable
+	if lc.spool != nil && !lc.localOnly {
+		lc.spool.drain(lc)
+	}
+
+	if lc.signalFlush {
+		lc.flushOnSignal()
+	}
+
+	return lc, nil
+}
+
+// Check the log targets can work, so a bad configuration fails at startup instead of logs going nowhere
+func (lc LoggingClient) validate() error {
+	if lc.RemoteUrl != "" {
+		u, err := url.Parse(lc.RemoteUrl)
+		if err != nil {
+			return fmt.Errorf("invalid remote url %s: %s", lc.RemoteUrl, err.Error())
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid remote url %s: must be an http or https url", lc.RemoteUrl)
+		}
+	}
+
+	if lc.LogFilePath != "" {
+		dir := filepath.Dir(datedPath(lc.LogFilePath, lc.RotateTimeLayout, lc.now()))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("invalid log file path %s: %s", lc.LogFilePath, err.Error())
+		}
+	}
+
+	for _, sink := range lc.fileSinks.sinks {
+		dir := filepath.Dir(datedPath(sink.path, lc.RotateTimeLayout, lc.now()))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("invalid csv file path %s: %s", sink.path, err.Error())
+		}
+	}
+
+	return nil
 }
 
 // Send the log out as a REST request
 func (lc LoggingClient) log(logLevel support_domain.LogLevel, msg string, labels []string) error {
-	// TODO: Find out if the log type is loggable (is it enabled)
+	return lc.logWithContext(context.Background(), logLevel, msg, labels)
+}
+
+// Log on behalf of the request the context belongs to
+func (lc LoggingClient) logWithContext(ctx context.Context, logLevel support_domain.LogLevel, msg string, labels []string) error {
+	if lc.discard {
+		return nil
+	}
+	lc = lc.initialized()
+
+	// Skip messages below the minimum log level
+	if !lc.allLevels && !lc.isLoggable(logLevel) {
+		return nil
+	}
+
+	// Mask secrets in the message before it is cut, so no part of one gets past the patterns
+	if lc.redactor != nil {
+		msg = lc.redactor.redactString(msg)
+	}
+
+	// A huge message would be rejected by the logging service, losing it entirely
+	if lc.MaxMessageSize > 0 && len(msg) > lc.MaxMessageSize {
+		if !lc.SplitMessages {
+			msg = truncateMessage(msg, lc.MaxMessageSize)
+		} else {
+			chunks := splitMessage(msg, lc.MaxMessageSize)
+			for i, chunk := range chunks {
+				part := fmt.Sprintf("part:%d/%d", i+1, len(chunks))
+				if err := lc.logWithContext(ctx, logLevel, chunk, append(labels[:len(labels):len(labels)], part)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	if lc.deduplicator != nil && !lc.deduplicator.allow(lc, logLevel, msg) {
+		return nil
+	}
+	if lc.sampler != nil && !lc.sampler.allow(lc, logLevel, msg) {
+		return nil
+	}
+	atomic.AddUint64(&lc.stats.logged[logLevels[logLevel]], 1)
+
+	// Build the entry straight away so it records when the event happened,
+	// not when (or how many times) delivery was attempted
+	logEntry := lc.buildLogEntry(logLevel, msg, labels)
+	logEntry.CorrelationID = lc.correlationID(ctx)
+	if lc.stackLevel != "" && atLeast(logLevel, lc.stackLevel) {
+		logEntry.Stack = callerStack()
+	}
+	lc.addContextFields(ctx, &logEntry)
+
+	// Mask secrets in the labels and fields before the entry goes to hooks or any output
+	if lc.redactor != nil {
+		lc.redactor.redact(&logEntry)
+	}
+
+	lc.fireHooks(logEntry)
+
+	// Syslog gets the message, escaped so logged data can't forge lines
+	line := formatMessage(logEntry)
+	if !lc.rawMessages {
+		line = escapeControl(line)
+	}
+	// The stack trace is meant to span lines, one per frame
+	if logEntry.Stack != "" {
+		line += "\n" + logEntry.Stack
+	}
+
+	// Stdout and the log files each get whatever their formatter makes of the entry, text lines
+	// unless set otherwise. Formatted before locking, so a slow formatter doesn't hold up other logs.
+	var stdOutLine, fileLine string
+	toStdOut := lc.EnableStdOut && atLeast(logLevel, lc.stdOutLevel)
+	if toStdOut {
+		stdOutLine = lc.formatLocal(lc.stdOutFormatter, logEntry, line)
+		// Colors are only for people watching stdout, never for the file or the logging service
+		if lc.color {
+			stdOutLine = colorPrefix(logLevel, stdOutLine)
+		}
+	}
+	if lc.logFilePath() != "" || lc.fileSinks.hasSinks() {
+		fileLine = lc.formatLocal(lc.fileFormatter, logEntry, line)
+	}
+
+	// Writes to the shared writers and files must not interleave
+	lc.mutex.Lock()
+	if toStdOut {
+		lc.consoleLogger(logLevel).Println(stdOutLine)
+		lc.flushStdOut(logLevel)
+	}
+
+	// The file and the logging service are independent, each is skipped when its target is empty
 
 	// Save to logging file if path was set
-	lc.saveToLogFile(string(logLevel), msg)
+	fileErrs := lc.saveToLogFile(logEntry, fileLine)
+	lc.mutex.Unlock()
+
+	// Reported once the mutex is released, since the error handler may log
+	for _, err := range fileErrs {
+		lc.reportError(err)
+	}
+
+	// Send to syslog if configured, it does its own locking
+	if lc.syslog != nil {
+		if err := lc.syslog.write(logLevel, line); err != nil {
+			lc.reportError(fmt.Errorf("error writing to syslog: %s", err.Error()))
+		}
+	}
+
+	// The remote level and label filter hold for every transport that ships entries off the
+	// node, but not for alerts, which have a level of their own
+	remote := atLeast(logEntry.Level, lc.remoteLevel) && lc.remoteLabelsMatch(logEntry.Labels)
+
+	// Send to other transports, a failure there shouldn't stop the logging service getting the entry
+	for _, sink := range lc.sinks {
+		if _, alerts := sink.(*alertSink); !remote && !alerts {
+			continue
+		}
+		if err := sink.send(lc, logEntry); err != nil {
+			lc.reportError(err)
+		}
+	}
 
 	// Send to logging service
-	logEntry := lc.buildLogEntry(logLevel, msg, labels)
-	return lc.sendLog(logEntry)
+	if !remote {
+		return nil
+	}
+	return lc.sendLog(ctx, logEntry)
 }
 
-func (lc LoggingClient) saveToLogFile(prefix string, message string) {
-	if lc.LogFilePath != "" {
-		file, err := os.OpenFile(lc.LogFilePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-		defer file.Close()
-		if err != nil {
-			fmt.Println("Error opening log file: " + err.Error())
+// Logger printing lines of the level: stdout, or stderr for warnings and errors with WithStderrForErrors
+func (lc LoggingClient) consoleLogger(logLevel support_domain.LogLevel) *log.Logger {
+	if lc.stdErrLogger != nil && atLeast(logLevel, support_domain.WARN) {
+		return lc.stdErrLogger
+	}
+	return lc.stdOutLogger
+}
+
+// Check whether messages of the given level pass the minimum log level
+func (lc LoggingClient) isLoggable(logLevel support_domain.LogLevel) bool {
+	return logLevels[logLevel] >= logLevels[lc.minLevel()]
+}
+
+// The minimum log level, as last set on this client or any copy of it
+func (lc LoggingClient) minLevel() support_domain.LogLevel {
+	return lc.targets.logLevel.Load().(support_domain.LogLevel)
+}
+
+// Whether messages of the level are logged, so expensive work building a message can be skipped
+func (lc LoggingClient) IsEnabled(logLevel support_domain.LogLevel) bool {
+	return !lc.discard && lc.initialized().isLoggable(logLevel)
+}
+
+// Set the minimum level of messages to log (TRACE logs everything), for every copy of the client
+func (lc LoggingClient) SetLogLevel(logLevel support_domain.LogLevel) error {
+	if _, ok := logLevels[logLevel]; !ok {
+		return fmt.Errorf("unknown log level: %s", logLevel)
+	}
+	lc = lc.initialized()
+	lc.targets.levelMutex.Lock()
+	defer lc.targets.levelMutex.Unlock()
+
+	// Replaces any temporary level, which then isn't reverted
+	lc.stopLevelTimer()
+	lc.targets.logLevel.Store(logLevel)
+	return nil
+}
+
+// Log at the given level (usually a more verbose one, like DEBUG) for d, then go back to the
+// level from before. Calling it again before d is up extends the time, still going back to
+// the original level; SetLogLevel meanwhile cancels going back.
+func (lc LoggingClient) SetLevelFor(logLevel support_domain.LogLevel, d time.Duration) error {
+	if _, ok := logLevels[logLevel]; !ok {
+		return fmt.Errorf("unknown log level: %s", logLevel)
+	}
+	lc = lc.initialized()
+
+	lc.targets.levelMutex.Lock()
+	defer lc.targets.levelMutex.Unlock()
+
+	restore := lc.minLevel()
+	if lc.targets.levelTimer != nil {
+		restore = lc.targets.restore
+	}
+	lc.stopLevelTimer()
+
+	lc.targets.logLevel.Store(logLevel)
+	lc.targets.restore = restore
+	gen := lc.targets.levelGen
+	lc.targets.levelTimer = time.AfterFunc(d, func() {
+		lc.targets.levelMutex.Lock()
+		defer lc.targets.levelMutex.Unlock()
+
+		// Only if the level wasn't changed again since
+		if lc.targets.levelGen == gen {
+			lc.targets.logLevel.Store(restore)
+			lc.stopLevelTimer()
+		}
+	})
+	return nil
+}
+
+// Cancel reverting a temporary level. Must be called with the level mutex held.
+func (lc LoggingClient) stopLevelTimer() {
+	if lc.targets.levelTimer != nil {
+		lc.targets.levelTimer.Stop()
+		lc.targets.levelTimer = nil
+	}
+	lc.targets.levelGen++
+}
+
+// Set a function to be called when a log could not be delivered to the logging service.
+// Remote logging is fire-and-forget, so this is the only way to observe failed sends.
+// This affects every copy of the client.
+func (lc LoggingClient) SetErrorHandler(handler func(error)) {
+	lc = lc.initialized()
+	lc.callbacks.mutex.Lock()
+	defer lc.callbacks.mutex.Unlock()
+
+	lc.callbacks.errorHandler = handler
+}
+
+// The function set with SetErrorHandler, or nil
+func (lc LoggingClient) errorHandler() func(error) {
+	if lc.callbacks == nil {
+		return nil
+	}
+	lc.callbacks.mutex.RLock()
+	defer lc.callbacks.mutex.RUnlock()
+	return lc.callbacks.errorHandler
+}
+
+// Report a failed delivery to the error handler, or else print it with the logs.
+// Must not be called with the mutex held.
+func (lc LoggingClient) reportError(err error) {
+	if handler := lc.errorHandler(); handler != nil {
+		handler(err)
+		return
+	}
+	lc.printNotice(support_domain.WARN, err.Error())
+}
+
+// Switch logging to the logging service at url while the service is running, for example
+// to fail over to a backup collector. This affects every copy of the client.
+func (lc LoggingClient) SetRemoteURL(url string) {
+	lc = lc.initialized()
+	lc.targets.remoteUrl.Store(url)
+}
+
+// Switch logging to the file at path while the service is running, or stop writing to a
+// file with an empty path. This affects every copy of the client. If the new file can't
+// be opened, the error is returned and logging carries on to the current file.
+func (lc LoggingClient) SetLogFile(path string) error {
+	lc = lc.initialized()
+	path = cleanPath(path)
+
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	if path == "" {
+		if err := lc.logFile.close(); err != nil {
+			return err
+		}
+	} else if err := lc.logFile.reopen(datedPath(path, lc.RotateTimeLayout, lc.now())); err != nil {
+		return err
+	}
+
+	lc.targets.logFilePath.Store(path)
+	return nil
+}
+
+// URL of the logging service, as changed by SetRemoteURL if it was called
+func (lc LoggingClient) remoteUrl() string {
+	if lc.localOnly {
+		return ""
+	}
+	if url, ok := lc.targets.remoteUrl.Load().(string); ok {
+		return url
+	}
+	return lc.RemoteUrl
+}
+
+// Path of the log file, as changed by SetLogFile if it was called
+func (lc LoggingClient) logFilePath() string {
+	if path, ok := lc.targets.logFilePath.Load().(string); ok {
+		return path
+	}
+	return lc.LogFilePath
+}
+
+// Write the line to the log files that take its level, returning the problems to report
+func (lc LoggingClient) saveToLogFile(entry LogEntry, message string) []error {
+	var errs []error
+	logLevel := entry.Level
+	if logFilePath := lc.logFilePath(); logFilePath != "" && atLeast(logLevel, lc.fileLevel) {
+		errs = appendError(errs, lc.writeLogFile(lc.logFile, logFilePath, logLevel, message))
+	}
+
+	// Files added with AddFileSink only get the levels they asked for
+	for _, sink := range lc.fileSinks.sinks {
+		if !atLeast(logLevel, sink.minLevel) {
+			continue
+		}
+		if sink.csv {
+			errs = appendError(errs, lc.writeLogFile(sink.file, sink.path, logLevel, formatCSV(entry, entryTime(entry).Format(lc.timeFormat))))
+		} else {
+			errs = appendError(errs, lc.writeLogFile(sink.file, sink.path, logLevel, message))
+		}
+	}
+	return errs
+}
+
+func appendError(errs []error, err error) []error {
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Write a line to one of the log files. Callers hold the mutex, and report the returned error
+// once it is released.
+func (lc LoggingClient) writeLogFile(lf *logFile, logFilePath string, logLevel support_domain.LogLevel, message string) error {
+	// When rotating by time, a new period means a new path, which reopens the file.
+	// Checking on each write means an idle service rotates on its next log.
+	path := datedPath(logFilePath, lc.RotateTimeLayout, lc.now())
+	if err := lf.open(path); err != nil {
+		// Don't lose the log, it's most needed when something is misconfigured
+		if !lc.EnableStdOut {
+			lc.consoleLogger(logLevel).Println(message)
+			lc.flushStdOut(logLevel)
+		}
+
+		// Report the problem once rather than on every log
+		if !lf.openFailed {
+			lf.openFailed = true
+			return fmt.Errorf("error opening log file, logging to stdout instead: %s", err.Error())
+		}
+		return nil
+	}
+	lf.openFailed = false
+
+	// Start a new file once this one has grown too big. The mutex is held,
+	// so no other goroutine can be writing to the file while it is rotated.
+	// The line is still written if that fails.
+	var reported error
+	if lc.MaxFileSize > 0 && lf.size >= lc.MaxFileSize {
+		if err := lf.rotate(lc.MaxBackups); err != nil {
+			reported = fmt.Errorf("error rotating log file: %s", err.Error())
+		}
+	}
+
+	lc.fileLogger.SetOutput(lf)
+	lc.fileLogger.Println(message)
+
+	// Errors are written out straight away in case the service is about to crash
+	if logLevel == support_domain.ERROR {
+		if err := lf.flush(); err != nil && reported == nil {
+			reported = fmt.Errorf("error writing log file: %s", err.Error())
+		}
+	} else {
+		lf.scheduleFlush(lc.fileFlushInterval, func() {
+			lc.flushLogFile(lf)
+		})
+	}
+	return reported
+}
+
+// Called by a log file's timer to write out buffered lines
+func (lc LoggingClient) flushLogFile(lf *logFile) {
+	lc.mutex.Lock()
+	lf.flushTimer = nil
+	err := lf.flush()
+	lc.mutex.Unlock()
+
+	if err != nil {
+		lc.reportError(fmt.Errorf("error writing log file: %s", err.Error()))
+	}
+}
+
+// Also write logs at or above minLevel to the file at path, for example to keep errors
+// in their own file. The file is rotated and buffered like the main log file.
+func (lc LoggingClient) AddFileSink(path string, minLevel support_domain.LogLevel) error {
+	if _, ok := logLevels[minLevel]; !ok {
+		return fmt.Errorf("unknown log level: %s", minLevel)
+	}
+	lc = lc.initialized()
+
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+
+	// Open the file now so a bad path is reported to the caller
+	path = cleanPath(path)
+	sink := &fileSink{path: path, minLevel: minLevel, file: &logFile{bufferSize: lc.logFile.bufferSize}}
+	if err := sink.file.open(datedPath(path, lc.RotateTimeLayout, lc.now())); err != nil {
+		return err
+	}
+
+	lc.fileSinks.add(sink)
+	return nil
+}
+
+// Get a client that attaches the given fields to every log, in addition to any
+// fields already attached to this client. The new client shares this client's outputs.
+func (lc LoggingClient) WithFields(fields map[string]interface{}) LoggingClient {
+	merged := make(map[string]interface{}, len(lc.fields)+len(fields))
+	for key, value := range lc.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	lc.fields = merged
+	return lc
+}
+
+// Get a logger adding the given field to every message, on top of the fields already added
+func (lc LoggingClient) WithField(key string, value interface{}) LoggingClient {
+	return lc.WithFields(map[string]interface{}{key: value})
+}
+
+// Get a logger adding the given labels to every message. It shares the outputs and settings
+// of lc, so changing the level of one changes both.
+//
+// Labels are merged, never replaced: an entry gets the labels of lc, then the new labels,
+// then the labels passed with the message, with any repeat of a label left out. So a filter
+// on a label set with With matches every message of the derived logger.
+func (lc LoggingClient) With(labels ...string) LoggingClient {
+	merged := mergeLabels(lc.labels, labels)
+	// Without spare capacity entries can share the slice, appending to it always copies
+	lc.labels = merged[:len(merged):len(merged)]
+	return lc
+}
+
+// Get a logger whose entries name origin as the service they come from, for a component
+// relaying logs on behalf of others. It shares the outputs and settings of lc.
+func (lc LoggingClient) LogAs(origin string) LoggingClient {
+	lc.owningServiceName = origin
+	return lc
+}
+
+// Append extra to base without duplicates, keeping the first occurrence of each label.
+// Always returns a fresh slice, so appending never writes into the labels of another entry.
+func mergeLabels(base []string, extra []string) []string {
+	merged := make([]string, 0, len(base)+len(extra))
+	seen := make(map[string]bool, len(base)+len(extra))
+	for _, labels := range [][]string{base, extra} {
+		for _, label := range labels {
+			if !seen[label] {
+				seen[label] = true
+				merged = append(merged, label)
+			}
 		}
+	}
+	return merged
+}
+
+// Labels of an entry: those added with With, then those passed with the message.
+// Entries share the labels of lc when no others are passed, so only labels passed
+// with the message cost an allocation.
+func (lc LoggingClient) entryLabels(labels []string) []string {
+	if len(labels) == 0 {
+		return lc.labels
+	}
+	if len(lc.labels) == 0 {
+		return labels
+	}
 
-		lc.fileLogger.SetOutput(file)
-		lc.fileLogger.SetPrefix(prefix + ": ")
-		lc.fileLogger.Println(message)
+	// The labels of lc are free of duplicates already, so only the others are checked
+	merged := make([]string, len(lc.labels), len(lc.labels)+len(labels))
+	copy(merged, lc.labels)
+	for _, label := range labels {
+		if !containsLabel(merged, label) {
+			merged = append(merged, label)
+		}
 	}
+	return merged
 }
 
 // Log an INFO level message
 func (lc LoggingClient) Info(msg string, labels ...string) error {
-	lc.stdOutLogger.SetPrefix("INFO: ")
-	lc.stdOutLogger.Println(msg)
 	return lc.log(support_domain.INFO, msg, labels)
 }
 
 // Log a TRACE level message
-func (lc LoggingClient) Trace(msg string, labels ...string) {
-
+func (lc LoggingClient) Trace(msg string, labels ...string) error {
+	return lc.log(support_domain.TRACE, msg, labels)
 }
 
 // Log a DEBUG level message
 func (lc LoggingClient) Debug(msg string, labels ...string) error {
-	lc.stdOutLogger.SetPrefix("DEBUG: ")
-	lc.stdOutLogger.Println(msg)
 	return lc.log(support_domain.DEBUG, msg, labels)
 }
 
 // Log a WARN level message
 func (lc LoggingClient) Warn(msg string, labels ...string) error {
-	lc.stdOutLogger.SetPrefix("WARN: ")
-	lc.stdOutLogger.Println(msg)
 	return lc.log(support_domain.WARN, msg, labels)
 }
 
 // Log an ERROR level message
 func (lc LoggingClient) Error(msg string, labels ...string) error {
-	lc.stdOutLogger.SetPrefix("ERROR: ")
-	lc.stdOutLogger.Println(msg)
 	return lc.log(support_domain.ERROR, msg, labels)
 }
 
+// Log a formatted INFO level message
+func (lc LoggingClient) Infof(format string, args ...interface{}) error {
+	if !lc.IsEnabled(support_domain.INFO) {
+		return nil
+	}
+	return lc.Info(fmt.Sprintf(format, args...))
+}
+
+// Log a formatted TRACE level message
+func (lc LoggingClient) Tracef(format string, args ...interface{}) error {
+	if !lc.IsEnabled(support_domain.TRACE) {
+		return nil
+	}
+	return lc.Trace(fmt.Sprintf(format, args...))
+}
+
+// Log a formatted DEBUG level message
+func (lc LoggingClient) Debugf(format string, args ...interface{}) error {
+	if !lc.IsEnabled(support_domain.DEBUG) {
+		return nil
+	}
+	return lc.Debug(fmt.Sprintf(format, args...))
+}
+
+// Log a formatted WARN level message
+func (lc LoggingClient) Warnf(format string, args ...interface{}) error {
+	if !lc.IsEnabled(support_domain.WARN) {
+		return nil
+	}
+	return lc.Warn(fmt.Sprintf(format, args...))
+}
+
+// Log a formatted ERROR level message
+func (lc LoggingClient) Errorf(format string, args ...interface{}) error {
+	if !lc.IsEnabled(support_domain.ERROR) {
+		return nil
+	}
+	return lc.Error(fmt.Sprintf(format, args...))
+}
+
+// Log an ERROR level message, wait for it to reach every output and exit the process
+func (lc LoggingClient) Fatal(msg string, labels ...string) {
+	lc.logFinal(msg, labels)
+	if err := lc.Close(); err != nil {
+		// Stdout is closed along with the client, and the process is about to exit
+		fmt.Println(err.Error())
+	}
+	os.Exit(lc.exitCode)
+}
+
+// Log a formatted ERROR level message, wait for it to reach every output and exit the process
+func (lc LoggingClient) Fatalf(format string, args ...interface{}) {
+	lc.Fatal(fmt.Sprintf(format, args...))
+}
+
+// Log an ERROR level message, wait for it to reach every output and panic with the message
+func (lc LoggingClient) Panic(msg string, labels ...string) {
+	lc.logFinal(msg, labels)
+	panic(msg)
+}
+
+// Log a formatted ERROR level message, wait for it to reach every output and panic with the message
+func (lc LoggingClient) Panicf(format string, args ...interface{}) {
+	lc.Panic(fmt.Sprintf(format, args...))
+}
+
+// Log an ERROR level message before the goroutine or process goes down. The remote send
+// is synchronous since an asynchronous one may not get to run before the exit.
+func (lc LoggingClient) logFinal(msg string, labels []string) {
+	// Send anything batched earlier first to keep the order
+	if err := lc.Flush(); err != nil {
+		lc.reportError(err)
+	}
+
+	final := lc
+	final.Synchronous = true
+	final.BatchSize = 0
+	if err := final.log(support_domain.ERROR, msg, labels); err != nil {
+		lc.reportError(err)
+	}
+}
+
 // Build the log entry object
-func (lc LoggingClient) buildLogEntry(logLevel support_domain.LogLevel, msg string, labels []string) support_domain.LogEntry {
-	res := support_domain.LogEntry{}
+func (lc LoggingClient) buildLogEntry(logLevel support_domain.LogLevel, msg string, labels []string) LogEntry {
+	res := LogEntry{SchemaVersion: SchemaVersion}
 	res.Level = logLevel
 	res.Message = msg
-	res.Labels = labels
+	res.Labels = lc.entryLabels(labels)
 	res.OriginService = lc.owningServiceName
+	res.time = lc.now()
+	res.Created = makeTimestamp(res.time)
+	res.Fields = lc.fields
+	res.Hostname = lc.hostname
+	res.Pid = os.Getpid()
 
 	return res
 }
 
+// Transport for requests to the logging service, pooling connections for reuse
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = maxIdleConnsPerHost
+	return transport
+}
+
+// Current time in milliseconds since the epoch
+func makeTimestamp(now time.Time) int64 {
+	return now.UnixNano() / int64(time.Millisecond)
+}
+
 // Send the log as an http request
-func (lc LoggingClient) sendLog(logEntry support_domain.LogEntry) error {
-	if lc.RemoteUrl == "" {
+func (lc LoggingClient) sendLog(ctx context.Context, logEntry LogEntry) error {
+	if lc.remoteUrl() == "" {
+		return nil
+	}
+
+	// Hold the entry back until the batch is full or the flush interval elapses
+	if lc.BatchSize > 1 {
+		entries := lc.batch.add(logEntry, lc.BatchSize, lc.FlushInterval, lc.flushOnTimer)
+		if entries == nil {
+			return nil
+		}
+
+		// The batch holds entries from other requests, so it is sent regardless of ctx
+		return lc.send(context.Background(), entries, lc.flushed(entries))
+	}
+
+	return lc.send(ctx, logEntry, nil)
+}
+
+// Whether an entry with these labels should leave the node: it needs one of the
+// included labels, if any were given, and none of the excluded ones
+func (lc LoggingClient) remoteLabelsMatch(labels []string) bool {
+	included := len(lc.remoteInclude) == 0
+	for _, label := range labels {
+		if containsLabel(lc.remoteExclude, label) {
+			return false
+		}
+		if !included && containsLabel(lc.remoteInclude, label) {
+			included = true
+		}
+	}
+	return included
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
+
+// Send the batched log entries to the logging service now, waiting for the result.
+// Call this before shutting down so entries still in the batch aren't lost.
+func (lc LoggingClient) Flush() error {
+	if lc.batch == nil {
+		return nil
+	}
+	entries := lc.batch.flush()
+	if len(entries) == 0 || lc.remoteUrl() == "" {
+		return nil
+	}
+
+	req, err := lc.newRequest(entries)
+	if err == nil {
+		err = lc.deliverPayload(req, entries)
+	}
+	if done := lc.flushed(entries); done != nil {
+		done(err)
+	}
+	return err
+}
+
+// Functions set while the client is in use, shared by every copy of the client
+type callbacks struct {
+	mutex        sync.RWMutex
+	errorHandler func(error)
+	onFlush      func([]LogEntry, error)
+}
+
+// Set a function to be called after each batch is sent, with its entries and the delivery
+// error (nil on success), to record metrics or queue failed batches again. It runs after
+// any lock is released, so it may log. This affects every copy of the client.
+func (lc LoggingClient) OnFlush(callback func(entries []LogEntry, err error)) {
+	lc = lc.initialized()
+	lc.callbacks.mutex.Lock()
+	defer lc.callbacks.mutex.Unlock()
+
+	lc.callbacks.onFlush = callback
+}
+
+// Get the function reporting the outcome of sending a batch to the OnFlush callback, or nil if there is none
+func (lc LoggingClient) flushed(entries []LogEntry) func(error) {
+	lc.callbacks.mutex.RLock()
+	onFlush := lc.callbacks.onFlush
+	lc.callbacks.mutex.RUnlock()
+
+	if onFlush == nil {
 		return nil
 	}
+	return func(err error) {
+		onFlush(entries, err)
+	}
+}
 
-	reqBody, err := json.Marshal(logEntry)
+// Returned when sends are still in flight after the time allowed
+var errSendsTimedOut = errors.New("timed out waiting for logs to be sent to the logging service")
+
+// Wait (for at most timeout) until the asynchronous sends made so far are over, without closing
+// the client, for example in tests or to drain logs at a checkpoint. Entries still in the batch
+// aren't sent, call Flush first for those.
+func (lc LoggingClient) WaitForSends(timeout time.Duration) error {
+	if lc.sends != nil && !lc.sends.wait(timeout) {
+		return errSendsTimedOut
+	}
+	return nil
+}
+
+// Shut down the client: send any batched entries, wait (for a bounded time) for
+// sends still in flight and close the log file. Call this during graceful shutdown.
+// Nothing more is sent to the logging service afterwards.
+func (lc LoggingClient) Close() error {
+	// A zero value client has nothing to close
+	if lc.mutex == nil {
+		return nil
+	}
+	if lc.signalWatch != nil {
+		lc.signalWatch.stop()
+	}
+	err := lc.Flush()
+
+	// The workers exit once the sends already queued are made. Draining the spool stops after
+	// the request being sent, and the rest waits for the next run.
+	if lc.spool != nil {
+		lc.spool.close()
+	}
+	lc.queue.stop()
+	if (!lc.sends.wait(closeTimeout) || !lc.queue.join(closeTimeout)) && err == nil {
+		err = errSendsTimedOut
+	}
+
+	// Closed without the mutex, since a sink sending what is left may report failures
+	if lc.syslog != nil {
+		if closeErr := lc.syslog.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	for _, sink := range lc.sinks {
+		if closeErr := sink.close(lc); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	// Nothing more is sent, so connections kept for reuse (and their goroutines) can go
+	lc.httpClient.CloseIdleConnections()
+
+	// Stdout and the files last, so they have whatever was reported above
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	if lc.stdOutBuffer != nil {
+		lc.stdOutBuffer.flush()
+	}
+	if closeErr := lc.logFile.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	for _, sink := range lc.fileSinks.sinks {
+		if closeErr := sink.file.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Called by the batch's timer once the flush interval has elapsed
+func (lc LoggingClient) flushOnTimer() {
+	lc.sends.start()
+	defer lc.sends.finish()
+
+	if err := lc.Flush(); err != nil {
+		lc.reportError(err)
+	}
+}
+
+// Send a single entry or a batch of entries, unless ctx is canceled by the time the
+// send gets going. This happens in the background unless the client is synchronous.
+// done, if set, is called with the outcome once the send is over.
+func (lc LoggingClient) send(ctx context.Context, payload interface{}, done func(error)) error {
+	item := queuedSend{lc: lc, ctx: ctx, payload: payload, done: done}
+	req, err := lc.newRequest(payload)
 	if err != nil {
-		fmt.Println(err.Error())
+		lc.reportError(err)
+		if done != nil {
+			done(err)
+		}
 		return err
 	}
+	// Cancelation and the deadline of the caller's context apply to the request. With a
+	// RemoteTimeout as well, doRequest adds it to this context so the earlier deadline wins.
+	item.request = req.WithContext(ctx)
 
-	req, err := http.NewRequest("POST", lc.RemoteUrl, bytes.NewBuffer(reqBody))
-	if err != nil {
-		fmt.Println(err.Error())
+	// Wait for the logging service so the caller gets the real delivery error
+	if lc.Synchronous {
+		err := ctx.Err()
+		if err == nil {
+			err = lc.deliverPayload(item.request, payload)
+		}
+		if done != nil {
+			done(err)
+		}
 		return err
 	}
-	req.Header.Add("Content-Type", "application/json")
-	client := &http.Client{}
 
-	// Asynchronous call
-	go lc.makeRequest(client, req)
+	// Asynchronous call, through the bounded queue
+	lc.sends.start()
+	if err := lc.queue.push(item); err != nil {
+		item.finish(err)
+	}
 
 	return nil
 }
 
+// Build the request posting the payload to the logging service.
+// A batch is posted as a JSON array of entries rather than a single entry.
+func (lc LoggingClient) newRequest(payload interface{}) (*http.Request, error) {
+	reqBody, err := lc.marshalPayload(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMarshal, err.Error())
+	}
+	return lc.newPost(lc.queryUrl(lc.remoteUrl(), payload), "application/json", reqBody)
+}
+
+// Build a request posting the body to url, with the compression, headers and authorization
+// configured for the logging service
+func (lc LoggingClient) newPost(url string, contentType string, reqBody []byte) (*http.Request, error) {
+	var err error
+	compressed := lc.compress && len(reqBody) > compressionThreshold
+	if compressed {
+		if reqBody, err = gzipBytes(reqBody); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", contentType)
+	if compressed {
+		req.Header.Add("Content-Encoding", "gzip")
+	}
+
+	lc.addHeaders(req)
+	return req, nil
+}
+
+// Add the configured headers and authorization to a request
+func (lc LoggingClient) addHeaders(req *http.Request) {
+	// Applied to every request so headers (like rotating tokens) can be updated between logs.
+	// These replace the defaults set by newPost, so Content-Type can be overridden.
+	for key, values := range lc.RemoteHeaders {
+		req.Header.Del(key)
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	if lc.authorize != nil {
+		lc.authorize(req)
+	}
+}
+
+// Compress a request body
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // Function to call in a goroutine
-func (lc LoggingClient) makeRequest(client *http.Client, request *http.Request) {
-	resp, err := client.Do(request)
+func (lc LoggingClient) makeRequest(request *http.Request, payload interface{}) error {
+	// The failures that opened the circuit were reported, each skipped send needn't be
+	err := lc.deliverPayload(request, payload)
+	if err == nil {
+		if ended, suppressed := lc.outage.recovered(); ended {
+			lc.printNotice(support_domain.INFO, fmt.Sprintf("remote logging recovered (%d more failed sends were not reported)", suppressed))
+		}
+	} else if err != ErrCircuitOpen {
+		lc.reportSendError(err)
+	}
+	return err
+}
+
+// Report a failed send. The error handler gets every failure; without one only the
+// first failure of an outage, or a change in how sends fail, is printed.
+func (lc LoggingClient) reportSendError(err error) {
+	if lc.errorHandler() != nil || lc.outage.failed(err) {
+		lc.reportError(err)
+	}
+}
+
+// Messages that aren't cut any shorter when the logging service finds an entry too large
+const minTruncatedSize = 1024
+
+// Deliver the request for the entry or batch of entries. If the logging service finds it
+// too large (413) and SplitOnTooLarge is set, a batch is sent again in halves and the
+// message of a single entry is cut in half, as often as needed.
+func (lc LoggingClient) deliverPayload(request *http.Request, payload interface{}) error {
+	err := lc.deliverOrSpool(request)
+	if !lc.SplitOnTooLarge || !isTooLarge(err) {
+		return err
+	}
+
+	ctx := request.Context()
+	switch payload := payload.(type) {
+	case LogEntry:
+		return lc.deliverTruncated(ctx, payload, false, err)
+	case []LogEntry:
+		if len(payload) == 1 {
+			return lc.deliverTruncated(ctx, payload[0], true, err)
+		}
+		half := len(payload) / 2
+		err = lc.resend(ctx, payload[:half])
+		if secondErr := lc.resend(ctx, payload[half:]); err == nil {
+			err = secondErr
+		}
+	}
+	return err
+}
+
+// Send the entry or entries in a request of their own
+func (lc LoggingClient) resend(ctx context.Context, payload interface{}) error {
+	req, err := lc.newRequest(payload)
+	if err != nil {
+		return err
+	}
+	return lc.deliverPayload(req.WithContext(ctx), payload)
+}
+
+// Send the entry again with its message cut in half, until the logging service accepts it
+// or the message is down to minTruncatedSize. err is the outcome of the last attempt.
+func (lc LoggingClient) deliverTruncated(ctx context.Context, entry LogEntry, batched bool, err error) error {
+	for size := len(entry.Message) / 2; size >= minTruncatedSize && isTooLarge(err); size /= 2 {
+		smaller := entry
+		smaller.Message = truncateMessage(entry.Message, size)
+
+		var payload interface{} = smaller
+		if batched {
+			payload = []LogEntry{smaller}
+		}
+		req, reqErr := lc.newRequest(payload)
+		if reqErr != nil {
+			return reqErr
+		}
+		err = lc.deliverOrSpool(req.WithContext(ctx))
+	}
+	return err
+}
+
+// Deliver the request, keeping it in the spool (if there is one) to send later when it fails.
+// A success means the logging service is reachable again, so whatever was spooled is sent.
+func (lc LoggingClient) deliverOrSpool(request *http.Request) error {
+	err := lc.deliver(request)
+	if lc.spool == nil {
+		return err
+	}
+
 	if err == nil {
-		defer resp.Body.Close()
-		resp.Close = true
+		lc.spool.drain(lc)
+	} else if !isRejected(err) {
+		if spoolErr := lc.spool.save(request); spoolErr != nil {
+			lc.reportError(spoolErr)
+		}
+	}
+	return err
+}
+
+// Send the request, retrying failed attempts with exponential backoff,
+// unless the circuit breaker has stopped sends to the logging service
+func (lc LoggingClient) deliver(request *http.Request) error {
+	if lc.breaker == nil {
+		return lc.attempt(request)
+	}
+
+	if !lc.breaker.allow() {
+		atomic.AddUint64(&lc.stats.skipped, 1)
+		return ErrCircuitOpen
+	}
+	err := lc.attempt(request)
+	if request.Context().Err() != nil {
+		// The caller gave up on the request, which says nothing about the logging service
+		lc.breaker.abandon()
+	} else if isRejected(err) {
+		// The logging service is up, it only refused this request (or wants smaller ones)
+		lc.breaker.record(nil)
 	} else {
-		fmt.Println(err.Error())
+		lc.breaker.record(err)
+	}
+	return err
+}
+
+// Send the request, retrying failed attempts with exponential backoff
+func (lc LoggingClient) attempt(request *http.Request) error {
+	delay := lc.RetryBaseDelay
+	for attempt := 0; ; attempt++ {
+		retry, err := lc.doRequest(request)
+		if err == nil {
+			atomic.AddUint64(&lc.stats.sent, 1)
+			return nil
+		}
+		// Retrying is pointless once the context of the request is done
+		if !retry || attempt >= lc.MaxRetries || request.Context().Err() != nil {
+			atomic.AddUint64(&lc.stats.failed, 1)
+			return err
+		}
+
+		// Back off exponentially before the next attempt, unless the caller gives up meanwhile
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-request.Context().Done():
+			timer.Stop()
+			atomic.AddUint64(&lc.stats.failed, 1)
+			return err
+		}
+		delay *= 2
+
+		// The previous attempt consumed the body, so rewind it
+		body, err := request.GetBody()
+		if err != nil {
+			return err
+		}
+		request.Body = body
+	}
+}
+
+// Make a single attempt at delivering the request, reporting whether a failure is worth retrying
+func (lc LoggingClient) doRequest(request *http.Request) (bool, error) {
+	// Abandon the request if the logging service does not answer in time.
+	// The timeout is applied per request so the shared client picks up changes to RemoteTimeout.
+	if lc.RemoteTimeout > 0 {
+		ctx, cancel := context.WithTimeout(request.Context(), lc.RemoteTimeout)
+		defer cancel()
+		request = request.WithContext(ctx)
 	}
+
+	resp, err := lc.httpClient.Do(request)
+	if err != nil {
+		return true, &sendError{err: err}
+	}
+	// Drain the body so the connection can go back to the pool and be reused
+	defer func() {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+
+	// Anything other than a 2xx means the log was not accepted
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// A client error will fail the same way every time
+		statusErr := &RemoteStatusError{URL: request.URL.String(), StatusCode: resp.StatusCode, Status: resp.Status}
+		if body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody)); err == nil {
+			statusErr.Body = strings.TrimSpace(string(body))
+		}
+		return !statusErr.Rejected(), statusErr
+	}
+
+	// Some endpoints answer 2xx yet report failures in the body, like the Elasticsearch bulk api.
+	// Not retried, as the entries that did go through would be duplicated.
+	if check, ok := request.Context().Value(responseCheckKey).(func(io.Reader) error); ok {
+		if err := check(resp.Body); err != nil {
+			return false, &sendError{err: err}
+		}
+	}
+
+	return false, nil
+}
+
+// Context key of a function checking the body of a 2xx response, set with withResponseCheck
+const responseCheckKey = contextKey("response-check")
+
+// Have doRequest fail the request if check finds an error in the body of the response
+func withResponseCheck(request *http.Request, check func(io.Reader) error) *http.Request {
+	return request.WithContext(context.WithValue(request.Context(), responseCheckKey, check))
 }
@@ -0,0 +1,631 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+// Log from several goroutines until stop is closed
+func logUntil(lc LoggingClient, stop chan struct{}, wg *sync.WaitGroup) {
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					lc.Info("message")
+				}
+			}
+		}()
+	}
+}
+
+func TestSetTargetsWhileLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithLogFile(filepath.Join(dir, "a.log")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	logUntil(lc, stop, &wg)
+	for i := 0; i < 50; i++ {
+		lc.SetRemoteURL(server.URL + "/other")
+		if err := lc.SetLogFile(filepath.Join(dir, "b.log")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	if got := lc.remoteUrl(); got != server.URL+"/other" {
+		t.Errorf("remote url is %s", got)
+	}
+	if got := lc.logFilePath(); got != filepath.Join(dir, "b.log") {
+		t.Errorf("log file is %s", got)
+	}
+	if lc.RemoteUrl != server.URL {
+		t.Errorf("RemoteUrl changed to %s", lc.RemoteUrl)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSetLevelWhileLogging(t *testing.T) {
+	lc, err := New("test", WithWriter(io.Discard))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	logUntil(lc, stop, &wg)
+	for i := 0; i < 50; i++ {
+		lc.SetLogLevel(support_domain.WARN)
+		lc.SetLevelFor(support_domain.DEBUG, time.Millisecond)
+	}
+	lc.SetLogLevel(support_domain.ERROR)
+	close(stop)
+	wg.Wait()
+
+	if lc.IsEnabled(support_domain.WARN) || !lc.With("a").IsEnabled(support_domain.ERROR) {
+		t.Errorf("level is %s, want ERROR", lc.minLevel())
+	}
+}
+
+func TestSetLevelForReverts(t *testing.T) {
+	lc, err := New("test", WithWriter(io.Discard), WithLevel(support_domain.INFO))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc.SetLevelFor(support_domain.DEBUG, 20*time.Millisecond)
+	if !lc.IsEnabled(support_domain.DEBUG) {
+		t.Fatal("DEBUG not enabled by SetLevelFor")
+	}
+	time.Sleep(100 * time.Millisecond)
+	if lc.IsEnabled(support_domain.DEBUG) || !lc.IsEnabled(support_domain.INFO) {
+		t.Fatalf("level is %s after SetLevelFor expired, want INFO", lc.minLevel())
+	}
+}
+
+// Check every line starts with the level of its message, which names the level as well
+func checkPrefixes(t *testing.T, output string, lines int) {
+	t.Helper()
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		level := line[:strings.Index(line, ":")]
+		if !strings.Contains(line, " message from "+level) {
+			t.Fatalf("prefix doesn't match the message: %q", line)
+		}
+		count++
+	}
+	if count != lines {
+		t.Fatalf("%d lines written, want %d", count, lines)
+	}
+}
+
+func TestConcurrentLogging(t *testing.T) {
+	var stdout bytes.Buffer
+	path := filepath.Join(t.TempDir(), "app.log")
+	lc, err := New("test", WithWriter(&stdout), WithLogFile(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	levels := []support_domain.LogLevel{support_domain.TRACE, support_domain.DEBUG, support_domain.INFO, support_domain.WARN, support_domain.ERROR}
+	const goroutines, logs = 50, 100
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		logLevel := levels[i%len(levels)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < logs; j++ {
+				lc.log(logLevel, fmt.Sprintf("message from %s %d", logLevel, j), nil)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	checkPrefixes(t, stdout.String(), goroutines*logs)
+	checkPrefixes(t, readFile(t, path), goroutines*logs)
+}
+
+// Server collecting the request bodies posted to it and the messages of their entries, single or batched
+type collectingServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	bodies   []string
+	messages []string
+}
+
+func newCollectingServer(t *testing.T) *collectingServer {
+	s := &collectingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var entries []LogEntry
+		if bytes.HasPrefix(body, []byte("[")) {
+			json.Unmarshal(body, &entries)
+		} else {
+			var entry LogEntry
+			json.Unmarshal(body, &entry)
+			entries = append(entries, entry)
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.bodies = append(s.bodies, string(body))
+		for _, entry := range entries {
+			s.messages = append(s.messages, entry.Message)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *collectingServer) received() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.messages...)
+}
+
+func (s *collectingServer) requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.bodies...)
+}
+
+func TestCloseDeliversPendingEntries(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"queued":  nil,
+		"batched": {WithBatching(10, time.Hour)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := newCollectingServer(t)
+			lc, err := New("test", append([]Option{WithWriter(io.Discard), WithRemote(server.URL)}, opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			const logs = 25
+			for i := 0; i < logs; i++ {
+				lc.Info(fmt.Sprintf("message %d", i))
+			}
+			if err := lc.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if received := server.received(); len(received) != logs {
+				t.Fatalf("%d entries received after Close, want %d", len(received), logs)
+			}
+		})
+	}
+}
+
+func BenchmarkRemoteSend(b *testing.B) {
+	var conns int
+	var connsMutex sync.Mutex
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connsMutex.Lock()
+			conns++
+			connsMutex.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	lc, err := New("bench", WithoutConsole(), WithRemote(server.URL), WithSynchronous())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer lc.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lc.Info("message")
+	}
+	b.StopTimer()
+
+	// Connections are reused, so this stays at 1 however many sends are made
+	connsMutex.Lock()
+	b.ReportMetric(float64(conns), "conns")
+	connsMutex.Unlock()
+}
+
+func BenchmarkFileWrite(b *testing.B) {
+	for name, opts := range map[string][]Option{
+		"unbuffered": nil,
+		"buffered":   {WithFileBuffer(64*1024, time.Second)},
+	} {
+		b.Run(name, func(b *testing.B) {
+			lc, err := New("bench", append([]Option{WithoutConsole(), WithLogFile(filepath.Join(b.TempDir(), "app.log"))}, opts...)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer lc.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				lc.Info("message")
+			}
+		})
+	}
+}
+
+func TestSuppressedDebugfDoesNotAllocate(t *testing.T) {
+	lc, err := New("test", WithWriter(io.Discard), WithLevel(support_domain.INFO))
+	if err != nil {
+		t.Fatal(err)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		lc.Debugf("reading %d from %s", 42, "device")
+	})
+	if allocs != 0 {
+		t.Errorf("suppressed Debugf made %v allocations", allocs)
+	}
+}
+
+func BenchmarkSuppressedDebugf(b *testing.B) {
+	lc, err := New("bench", WithWriter(io.Discard), WithLevel(support_domain.INFO))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lc.Debugf("reading %d from %s", 42, "device")
+	}
+}
+
+// Labels of a sub-logger with a large fixed label set
+var tenLabels = []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+
+func TestBaseLabelsShared(t *testing.T) {
+	lc := NewNullClient().With(tenLabels...)
+	allocs := testing.AllocsPerRun(100, func() {
+		lc.entryLabels(nil)
+	})
+	if allocs != 0 {
+		t.Errorf("labels of an entry without labels of its own made %v allocations", allocs)
+	}
+}
+
+func BenchmarkSubLoggerLabels(b *testing.B) {
+	lc, err := New("bench", WithoutConsole())
+	if err != nil {
+		b.Fatal(err)
+	}
+	sub := lc.With(tenLabels...)
+
+	b.Run("base labels", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sub.Info("message")
+		}
+	})
+	b.Run("one more label", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sub.Info("message", "k")
+		}
+	})
+}
+
+func BenchmarkCaller(b *testing.B) {
+	for name, opt := range map[string]Option{
+		"on":  WithCallerLevel(support_domain.TRACE),
+		"off": WithCaller(false),
+	} {
+		b.Run(name, func(b *testing.B) {
+			lc, err := New("bench", WithWriter(io.Discard), opt)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lc.Debug("message")
+			}
+		})
+	}
+}
+
+// Writer counting the writes made to it, as a stand-in for the syscalls writing to stdout
+type countingWriter struct {
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), nil
+}
+
+func BenchmarkStdOutBuffer(b *testing.B) {
+	for name, opts := range map[string][]Option{
+		"unbuffered": nil,
+		"buffered":   {WithStdOutBuffer(64 * 1024)},
+	} {
+		b.Run(name, func(b *testing.B) {
+			stdout := &countingWriter{}
+			lc, err := New("bench", append([]Option{WithWriter(stdout)}, opts...)...)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				lc.Info("message")
+			}
+			lc.Close()
+
+			// Lock out the flush timer while counting
+			lc.mutex.Lock()
+			b.ReportMetric(float64(stdout.writes)/float64(b.N), "writes/op")
+			lc.mutex.Unlock()
+		})
+	}
+}
+
+func TestOutputLevels(t *testing.T) {
+	server := newCollectingServer(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithLogFile(path), WithRemote(server.URL), WithSynchronous(),
+		WithLevel(support_domain.DEBUG), WithStdOutLevel(support_domain.ERROR), WithFileLevel(support_domain.DEBUG), WithRemoteLevel(support_domain.WARN))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc.Trace("trace entry")
+	lc.Debug("debug entry")
+	lc.Warn("warn entry")
+	lc.Error("error entry")
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	file := readFile(t, path)
+	remote := strings.Join(server.received(), "\n")
+	outputs := []struct {
+		name   string
+		output string
+		want   []string
+		unwant []string
+	}{
+		{"stdout", stdout.String(), []string{"error entry"}, []string{"trace entry", "debug entry", "warn entry"}},
+		{"file", file, []string{"debug entry", "warn entry", "error entry"}, []string{"trace entry"}},
+		{"remote", remote, []string{"warn entry", "error entry"}, []string{"trace entry", "debug entry"}},
+	}
+	for _, output := range outputs {
+		for _, msg := range output.want {
+			if !strings.Contains(output.output, msg) {
+				t.Errorf("%s is missing %q", output.name, msg)
+			}
+		}
+		for _, msg := range output.unwant {
+			if strings.Contains(output.output, msg) {
+				t.Errorf("%s got %q", output.name, msg)
+			}
+		}
+	}
+}
+
+func TestOnFlushWhileLogging(t *testing.T) {
+	server := newCollectingServer(t)
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithBatching(5, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	logUntil(lc.With("copy"), stop, &wg)
+	var flushed int64
+	for i := 0; i < 100; i++ {
+		lc.OnFlush(func(entries []LogEntry, err error) {
+			atomic.AddInt64(&flushed, int64(len(entries)))
+		})
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The callback set on the client sees the batches of its copies
+	if atomic.LoadInt64(&flushed) == 0 {
+		t.Error("callback never called")
+	}
+}
+
+func TestSetErrorHandlerWhileLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithErrorHandler(func(error) {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	logUntil(lc.With("copy"), stop, &wg)
+
+	var reported int64
+	for i := 0; i < 100; i++ {
+		lc.SetErrorHandler(func(error) {
+			atomic.AddInt64(&reported, 1)
+		})
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(stop)
+	wg.Wait()
+	lc.Close()
+
+	// The handler set on the client gets the failures of its copies
+	if atomic.LoadInt64(&reported) == 0 {
+		t.Error("handler never called")
+	}
+}
+
+func TestAlertsIgnoreRemoteFilters(t *testing.T) {
+	server := newCollectingServer(t)
+	webhook := newCollectingServer(t)
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithRemoteLevel(support_domain.ERROR),
+		WithRemoteLabelFilter([]string{"audit"}, nil), WithAlertWebhook(webhook.URL, support_domain.WARN))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc.Warn("alerted")
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := server.received(); len(got) != 0 {
+		t.Errorf("sent %q that the remote filters leave out", got)
+	}
+	if got := webhook.received(); len(got) != 1 || got[0] != "alerted" {
+		t.Errorf("webhook received %q", got)
+	}
+}
+
+func TestSlowServerTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithSynchronous(), WithTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	err = lc.Error("message")
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("send took %s with a timeout of 100ms", elapsed)
+	}
+	if !errors.Is(err, ErrSendFailed) {
+		t.Errorf("got %v, want a failed send", err)
+	}
+}
+
+// Transport keeping the requests made through it, answering each with 200 OK
+type capturingTransport struct {
+	mutex    sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (c *capturingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.requests = append(c.requests, r)
+	c.bodies = append(c.bodies, body)
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader("")), Request: r}, nil
+}
+
+func TestTransportGetsRequest(t *testing.T) {
+	transport := &capturingTransport{}
+	clock := &manualClock{now: time.Date(2017, 11, 20, 10, 0, 0, 0, time.UTC)}
+	lc, err := New("test", WithWriter(io.Discard), WithRemote("http://logging.example/api/v1/logs"), WithSynchronous(),
+		WithTransport(transport), WithClock(clock), WithHeader("X-Tenant", "plant-7"), WithBearerToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Info("message", "label"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("%d requests, want 1", len(transport.requests))
+	}
+	request := transport.requests[0]
+	if request.Method != "POST" || request.URL.String() != "http://logging.example/api/v1/logs" {
+		t.Errorf("request %s %s", request.Method, request.URL)
+	}
+	for key, want := range map[string]string{
+		"Content-Type":  "application/json",
+		"X-Tenant":      "plant-7",
+		"Authorization": "Bearer secret",
+	} {
+		if got := request.Header.Get(key); got != want {
+			t.Errorf("header %s is %q, want %q", key, got, want)
+		}
+	}
+
+	want := lc.buildLogEntry(support_domain.INFO, "message", []string{"label"})
+	wantBody, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := transport.bodies[0]; !bytes.Equal(got, wantBody) {
+		t.Errorf("body %s, want %s", got, wantBody)
+	}
+}
@@ -0,0 +1,94 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"github.com/edgexfoundry/support-domain-go"
+	"strings"
+	"sync"
+)
+
+// Records log entries in memory, so tests can check what was logged
+type MemorySink struct {
+	mutex   sync.Mutex
+	entries []LogEntry
+}
+
+// Create a client for tests that only records what is logged, in the returned sink
+func NewTestClient() (LoggingClient, *MemorySink) {
+	sink := &MemorySink{}
+	lc, _ := New("test", WithoutConsole())
+	lc.AddHook(sink)
+	return lc, sink
+}
+
+// Record the entry. MemorySink is a Hook, so it can be added to any client with AddHook.
+func (s *MemorySink) Fire(entry LogEntry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Get the entries recorded so far, oldest first
+func (s *MemorySink) Entries() []LogEntry {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]LogEntry(nil), s.entries...)
+}
+
+// Whether an entry of the given level was recorded
+func (s *MemorySink) ContainsLevel(logLevel support_domain.LogLevel) bool {
+	for _, entry := range s.Entries() {
+		if entry.Level == logLevel {
+			return true
+		}
+	}
+	return false
+}
+
+// Whether an entry of the given level with a message containing text was recorded
+func (s *MemorySink) ContainsMessage(logLevel support_domain.LogLevel, text string) bool {
+	for _, entry := range s.Entries() {
+		if entry.Level == logLevel && strings.Contains(entry.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get the message of the last entry recorded, or "" if there is none
+func (s *MemorySink) LastMessage() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.entries) == 0 {
+		return ""
+	}
+	return s.entries[len(s.entries)-1].Message
+}
+
+// Forget the entries recorded so far
+func (s *MemorySink) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = nil
+}
@@ -0,0 +1,106 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+// Package mqttsink puts log entries on the EdgeX message bus, publishing each as JSON to a
+// topic on an MQTT broker. Levels can go to topics of their own or with a higher QoS.
+package mqttsink
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/support-domain-go"
+	"github.com/edgexfoundry/support-logging-client-go"
+	"sync"
+	"time"
+)
+
+// Wait between attempts to reach the MQTT broker
+const mqttRetryInterval = 10 * time.Second
+
+// Publishes log entries as JSON to a topic on an MQTT broker
+type Sink struct {
+	client      mqtt.Client
+	topic       string
+	levels      map[support_domain.LogLevel]mqttLevel
+	connectOnce sync.Once
+}
+
+// Where and how entries of one level are published
+type mqttLevel struct {
+	topic string
+	qos   byte
+}
+
+// Create a sink publishing log entries as JSON to topic on the MQTT broker (like
+// "tcp://localhost:1883"), connecting as clientID, for logger.WithSink. Entries are
+// published with QoS 0 unless changed with SetLevel.
+func New(broker string, topic string, clientID string) *Sink {
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectRetryInterval(mqttRetryInterval)
+
+	return &Sink{
+		client: mqtt.NewClient(opts),
+		topic:  topic,
+		levels: map[support_domain.LogLevel]mqttLevel{},
+	}
+}
+
+// Publish entries of the given level to topic (the sink's topic if empty) with the given QoS.
+// Call it before passing the sink to logger.WithSink.
+func (s *Sink) SetLevel(logLevel support_domain.LogLevel, topic string, qos byte) *Sink {
+	if topic == "" {
+		topic = s.topic
+	}
+	s.levels[logLevel] = mqttLevel{topic: topic, qos: qos}
+	return s
+}
+
+func (s *Sink) Send(entry logger.LogEntry) error {
+	// Connect on first use. Paho keeps trying in the background while the broker is
+	// unreachable, queueing what is published meanwhile, and reconnects if the connection drops.
+	s.connectOnce.Do(func() {
+		s.client.Connect()
+	})
+
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("%w: %s", logger.ErrMarshal, err.Error())
+	}
+
+	topic, qos := s.topic, byte(0)
+	if level, ok := s.levels[entry.Level]; ok {
+		topic, qos = level.topic, level.qos
+	}
+
+	// Delivery (and any QoS retries) happens in the background, logging doesn't wait for it
+	s.client.Publish(topic, qos, false, payload)
+	return nil
+}
+
+func (s *Sink) Close() error {
+	s.client.Disconnect(250)
+	return nil
+}
+
+func (s *Sink) String() string {
+	return "mqtt=" + s.topic
+}
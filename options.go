@@ -0,0 +1,549 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Configures a logging client created by New
+type Option func(*LoggingClient) error
+
+// Send logs to the logging service at url
+func WithRemote(url string) Option {
+	return func(lc *LoggingClient) error {
+		lc.RemoteUrl = url
+		return nil
+	}
+}
+
+// Write logs to the file at path
+func WithLogFile(path string) Option {
+	return func(lc *LoggingClient) error {
+		lc.LogFilePath = path
+		return nil
+	}
+}
+
+// Also write every log as a row of a CSV file at path, for spreadsheets, with a header row of
+// timestamp, level, service, message and labels. It is rotated along with the log file.
+func WithCSV(path string) Option {
+	return func(lc *LoggingClient) error {
+		// Opened on the first row, like the log file, so a client that never logs leaves no file.
+		// New checks the directory.
+		sink := &fileSink{path: cleanPath(path), csv: true, file: &logFile{bufferSize: lc.logFile.bufferSize, header: csvHeader}}
+		lc.fileSinks.add(sink)
+		return nil
+	}
+}
+
+// Buffer up to size bytes of writes to the log file, writing them out at least every
+// flushInterval. ERROR logs are written out immediately, and everything on Close.
+func WithFileBuffer(size int, flushInterval time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		lc.logFile.bufferSize = size
+		lc.fileFlushInterval = flushInterval
+		return nil
+	}
+}
+
+// Start a new log file once the current one reaches maxSize bytes, keeping
+// up to maxBackups old files named <path>.1 (most recent) to <path>.<maxBackups>
+func WithFileRotation(maxSize int64, maxBackups int) Option {
+	return func(lc *LoggingClient) error {
+		lc.MaxFileSize = maxSize
+		lc.MaxBackups = maxBackups
+		return nil
+	}
+}
+
+// Start a new log file every day, named like app-2017-11-20.log for a path of app.log.
+// Put {date} in the path to choose where the date goes instead.
+func WithDailyRotation() Option {
+	return WithTimeRotation("2006-01-02")
+}
+
+// Start a new log file whenever the current time formatted with layout changes (for
+// example "2006-01-02-15" for hourly files), putting it in the path as for WithDailyRotation
+func WithTimeRotation(layout string) Option {
+	return func(lc *LoggingClient) error {
+		lc.RotateTimeLayout = layout
+		return nil
+	}
+}
+
+// Write one JSON object per line to stdout and the log file instead of text lines,
+// for log collectors that parse structured logs
+func WithJSONOutput() Option {
+	return func(lc *LoggingClient) error {
+		lc.jsonOutput = true
+		return nil
+	}
+}
+
+// Write messages to stdout, the log file and syslog as they are. By default line breaks and
+// other control characters are escaped, so logged data can't forge log lines or send escape
+// sequences to a terminal; use this for messages that are meant to span several lines.
+func WithRawMessages() Option {
+	return func(lc *LoggingClient) error {
+		lc.rawMessages = true
+		return nil
+	}
+}
+
+// Write logfmt lines (time=... level=info msg=... key=value) to stdout and the log file
+// instead of text lines, for grep and logfmt tooling
+func WithLogfmt() Option {
+	return func(lc *LoggingClient) error {
+		lc.logfmtOutput = true
+		return nil
+	}
+}
+
+// Render entries for stdout and the log files with the formatter, instead of the built-in
+// text, JSON (WithJSONOutput) or logfmt (WithLogfmt) lines
+func WithFormatter(formatter Formatter) Option {
+	return func(lc *LoggingClient) error {
+		lc.stdOutFormatter = formatter
+		lc.fileFormatter = formatter
+		return nil
+	}
+}
+
+// Render entries for stdout with the formatter, for example text lines for people
+// watching while the log file gets JSON
+func WithStdOutFormatter(formatter Formatter) Option {
+	return func(lc *LoggingClient) error {
+		lc.stdOutFormatter = formatter
+		return nil
+	}
+}
+
+// Render entries for the log file and the files added with AddFileSink with the formatter.
+// CSV files keep their rows.
+func WithFileFormatter(formatter Formatter) Option {
+	return func(lc *LoggingClient) error {
+		lc.fileFormatter = formatter
+		return nil
+	}
+}
+
+// Format the timestamp of local log lines with layout, for example time.RFC3339Nano
+// for precise timestamps with a timezone. An empty layout leaves the timestamp out.
+func WithTimeFormat(layout string) Option {
+	return func(lc *LoggingClient) error {
+		lc.timeFormat = layout
+		return nil
+	}
+}
+
+// Include (the default) or leave out the file and line that logged on local log lines.
+// By default only WARN and ERROR lines have it, see WithCallerLevel.
+func WithCaller(include bool) Option {
+	return func(lc *LoggingClient) error {
+		lc.includeCaller = include
+		return nil
+	}
+}
+
+// Only include the file and line that logged on local lines at or above the level (WARN by
+// default), so frequent low level lines don't pay for finding the caller. TRACE includes it everywhere.
+func WithCallerLevel(logLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		return setOutputLevel(&lc.callerLevel, logLevel)
+	}
+}
+
+// Color the level of lines printed to stdout, when stdout is a terminal
+func WithColor() Option {
+	return func(lc *LoggingClient) error {
+		// Whether the output is a terminal is checked once all options are applied
+		lc.color = true
+		return nil
+	}
+}
+
+// Print the lines normally printed to stdout to w instead, for example a buffer in tests or
+// a writer of your own. Writes are serialized, so w needn't be safe for concurrent use.
+func WithWriter(w io.Writer) Option {
+	return func(lc *LoggingClient) error {
+		lc.stdOutLogger = log.New(w, "", 0)
+		return nil
+	}
+}
+
+// Print WARN and ERROR lines to stderr rather than stdout, for orchestrators that capture
+// and route the two streams separately. Lower levels still go to stdout.
+func WithStderrForErrors() Option {
+	return func(lc *LoggingClient) error {
+		lc.stdErrLogger = log.New(os.Stderr, "", 0)
+		return nil
+	}
+}
+
+// Only log messages at or above the given level
+func WithLevel(logLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		return lc.SetLogLevel(logLevel)
+	}
+}
+
+// Attach the stack of the code that logged to entries at or above minLevel, in their stack
+// field and after the message on text lines. At most 32 frames are kept.
+func WithStackTrace(minLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		return setOutputLevel(&lc.stackLevel, minLevel)
+	}
+}
+
+// Only print logs at or above the level to stdout. The level set with WithLevel or
+// SetLogLevel still applies to all outputs.
+func WithStdOutLevel(logLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		return setOutputLevel(&lc.stdOutLevel, logLevel)
+	}
+}
+
+// Only write logs at or above the level to the log file
+func WithFileLevel(logLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		return setOutputLevel(&lc.fileLevel, logLevel)
+	}
+}
+
+// Only send entries to the logging service and the other remote transports that carry one
+// of the include labels (any entry if include is empty) and none of the exclude labels.
+// Stdout, the log files, syslog and alerts still get every entry.
+func WithRemoteLabelFilter(include []string, exclude []string) Option {
+	return func(lc *LoggingClient) error {
+		lc.remoteInclude = include
+		lc.remoteExclude = exclude
+		return nil
+	}
+}
+
+// Only send logs at or above the level to the logging service and the other remote
+// transports, like WithSink. Alerts have their own level and aren't held to it.
+func WithRemoteLevel(logLevel support_domain.LogLevel) Option {
+	return func(lc *LoggingClient) error {
+		return setOutputLevel(&lc.remoteLevel, logLevel)
+	}
+}
+
+func setOutputLevel(outputLevel *support_domain.LogLevel, logLevel support_domain.LogLevel) error {
+	if _, ok := logLevels[logLevel]; !ok {
+		return fmt.Errorf("unknown log level: %s", logLevel)
+	}
+	*outputLevel = logLevel
+	return nil
+}
+
+// Abandon requests to the logging service after d
+func WithTimeout(d time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		lc.RemoteTimeout = d
+		return nil
+	}
+}
+
+// Retry failed requests to the logging service up to maxRetries times,
+// waiting baseDelay before the first retry and doubling it each time. A wait ends early
+// when the context of the log is canceled or times out.
+func WithRetries(maxRetries int, baseDelay time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		lc.MaxRetries = maxRetries
+		lc.RetryBaseDelay = baseDelay
+		return nil
+	}
+}
+
+// Replace anything matching one of the regular expressions with *** in the message, labels
+// and field values before it is logged anywhere. A field whose key matches is masked entirely.
+func WithRedaction(patterns ...string) Option {
+	return func(lc *LoggingClient) error {
+		redactor, err := newRedactor(patterns)
+		if err != nil {
+			return err
+		}
+		lc.redactor = redactor
+		return nil
+	}
+}
+
+// Truncate messages longer than size bytes (256 KiB by default), or with split set log them as
+// several entries labeled "part:1/3" and so on. A size of 0 removes the limit.
+func WithMaxMessageSize(size int, split bool) Option {
+	return func(lc *LoggingClient) error {
+		lc.MaxMessageSize = size
+		lc.SplitMessages = split
+		return nil
+	}
+}
+
+// Stop sending to the logging service after threshold failed sends in a row, until a single
+// probe send succeeds, made once cooldown has passed. Only sends that get no answer or a 5xx
+// count as failures. There is no circuit breaker unless this is given a threshold above 0.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		if threshold <= 0 {
+			lc.breaker = nil
+			return nil
+		}
+		lc.breaker = newCircuitBreaker(threshold, cooldown)
+		return nil
+	}
+}
+
+// Keep requests to the logging service that fail in files in dir, and send them once it is
+// reachable again, even after a restart. When the files add up to more than maxSize bytes the
+// oldest requests are dropped.
+func WithSpool(dir string, maxSize int64) Option {
+	return func(lc *LoggingClient) error {
+		spool, err := openSpool(dir, maxSize)
+		if err != nil {
+			return err
+		}
+		lc.spool = spool
+		return nil
+	}
+}
+
+// Take the current time from clock instead of the system clock, so tests can check
+// exact timestamps, time based rotation and deduplication windows. Flush intervals and the
+// timer closing a deduplication window still use real timers.
+func WithClock(clock Clock) Option {
+	return func(lc *LoggingClient) error {
+		lc.clock = clock
+		return nil
+	}
+}
+
+// On SIGTERM or SIGINT, send batched logs, write out buffered file writes and wait briefly for
+// sends in flight, then raise the signal again so it ends the process, or reaches the
+// application's own handlers. Close stops watching. Services with their own shutdown
+// handling should call Close from it instead.
+func WithSignalFlush() Option {
+	return func(lc *LoggingClient) error {
+		lc.signalFlush = true
+		return nil
+	}
+}
+
+// Collapse a message logged again and again back to back: repeats of the same level and
+// message within window of its first occurrence are counted, and logged as one line like
+// "no reading ... repeated 42 times" once another message arrives or the window closes
+func WithDeduplication(window time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		if window <= 0 {
+			return errors.New("deduplication needs a window greater than 0")
+		}
+		lc.deduplicator = &deduplicator{window: window}
+		return nil
+	}
+}
+
+// Log only the first initial occurrences of the same level and message in each interval,
+// then every thereafter-th one. How many were suppressed is logged when the interval ends.
+func WithSampling(initial int, thereafter int, interval time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		if interval <= 0 {
+			return errors.New("sampling needs an interval greater than 0")
+		}
+		lc.sampler = newSampler(initial, thereafter, interval)
+		return nil
+	}
+}
+
+// Don't check the remote url and log file directory when the client is created,
+// leaving any problem to be reported when logging
+func WithLazyValidation() Option {
+	return func(lc *LoggingClient) error {
+		lc.lazyValidation = true
+		return nil
+	}
+}
+
+// Send entries to the logging service in batches of size, or whatever is
+// pending once flushInterval has elapsed
+func WithBatching(size int, flushInterval time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		lc.BatchSize = size
+		lc.FlushInterval = flushInterval
+		return nil
+	}
+}
+
+// Wait for the logging service to accept each log before returning from the log call,
+// so the caller gets the delivery error and the log can't be lost if the process exits
+func WithSynchronous() Option {
+	return func(lc *LoggingClient) error {
+		lc.Synchronous = true
+		return nil
+	}
+}
+
+// Exit with code rather than 1 after logging with Fatal
+func WithExitCode(code int) Option {
+	return func(lc *LoggingClient) error {
+		lc.exitCode = code
+		return nil
+	}
+}
+
+// Queue up to size remote sends, sent by the given number of goroutines, and apply
+// the policy to sends that don't fit. The default is 1000 sends, 4 goroutines and DropNewest.
+func WithSendQueue(size int, workers int, policy DropPolicy) Option {
+	return func(lc *LoggingClient) error {
+		if size < 1 || workers < 1 {
+			return errors.New("the send queue needs a size and number of workers of at least 1")
+		}
+		lc.queue = newSendQueue(size, workers, policy, lc.stats)
+		return nil
+	}
+}
+
+// Gzip request bodies to the logging service over 1KB, such as batches. Only
+// use this if the logging service accepts gzip encoded requests.
+func WithCompression() Option {
+	return func(lc *LoggingClient) error {
+		lc.compress = true
+		return nil
+	}
+}
+
+// Call handler when a log could not be delivered to the logging service
+func WithErrorHandler(handler func(error)) Option {
+	return func(lc *LoggingClient) error {
+		lc.SetErrorHandler(handler)
+		return nil
+	}
+}
+
+// Discard every log instead of writing or sending it anywhere
+func WithDiscard() Option {
+	return func(lc *LoggingClient) error {
+		lc.discard = true
+		return nil
+	}
+}
+
+// Don't print logs to stdout, only to the log file and the logging service
+func WithoutConsole() Option {
+	return func(lc *LoggingClient) error {
+		lc.EnableStdOut = false
+		return nil
+	}
+}
+
+// Attach the fields found by fields in the context of the context-aware log methods, like
+// the IDs of the active trace (see the otelfields package). Can be given more than once.
+func WithContextFields(fields func(ctx context.Context) map[string]interface{}) Option {
+	return func(lc *LoggingClient) error {
+		lc.contextFields = append(lc.contextFields, fields)
+		return nil
+	}
+}
+
+// Read the correlation ID from the given context key in the context-aware log methods
+func WithCorrelationIDKey(key interface{}) Option {
+	return func(lc *LoggingClient) error {
+		lc.correlationIDKey = key
+		return nil
+	}
+}
+
+// Set a header on every request to the logging service, for example
+// an auth token or tenant header required by an API gateway
+func WithHeader(key string, value string) Option {
+	return func(lc *LoggingClient) error {
+		if lc.RemoteHeaders == nil {
+			lc.RemoteHeaders = http.Header{}
+		}
+		lc.RemoteHeaders.Set(key, value)
+		return nil
+	}
+}
+
+// Authenticate to the logging service with a fixed bearer token
+func WithBearerToken(token string) Option {
+	return WithTokenProvider(func() string {
+		return token
+	})
+}
+
+// Authenticate to the logging service with a bearer token fetched from provider
+// for every request, so tokens that expire can be refreshed
+func WithTokenProvider(provider func() string) Option {
+	return func(lc *LoggingClient) error {
+		lc.authorize = func(req *http.Request) {
+			req.Header.Set("Authorization", "Bearer "+provider())
+		}
+		return nil
+	}
+}
+
+// Authenticate to the logging service with HTTP basic auth
+func WithBasicAuth(username string, password string) Option {
+	return func(lc *LoggingClient) error {
+		lc.authorize = func(req *http.Request) {
+			req.SetBasicAuth(username, password)
+		}
+		return nil
+	}
+}
+
+// Use the TLS configuration for HTTPS requests to the logging service, for example to
+// trust a private CA, present a client certificate or (for development) skip verification
+func WithTLSConfig(config *tls.Config) Option {
+	return func(lc *LoggingClient) error {
+		// Installed once on the shared transport, so it and its connections are reused
+		transport, ok := lc.httpClient.Transport.(*http.Transport)
+		if !ok {
+			return errors.New("WithTLSConfig needs the default transport, configure TLS on a custom transport directly")
+		}
+		transport.TLSClientConfig = config
+		return nil
+	}
+}
+
+// Send requests to the logging service through the given transport, for example one going
+// through a proxy or one capturing the requests in tests. It replaces the default transport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(lc *LoggingClient) error {
+		lc.httpClient = &http.Client{Transport: transport}
+		return nil
+	}
+}
+
+// Send requests to the logging service with the given http client instead of the default one.
+// RemoteTimeout still applies to each request, on top of any timeout of the client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(lc *LoggingClient) error {
+		lc.httpClient = client
+		return nil
+	}
+}
@@ -0,0 +1,39 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+// Package otelfields finds the IDs of the OpenTelemetry span active in a context, for the
+// trace_id and span_id fields of log entries, so a backend can match logs to their traces.
+// Use it with logger.WithContextFields(otelfields.Fields).
+package otelfields
+
+import (
+	"context"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Get the trace_id and span_id of the span active in the context, or nothing without one.
+// Pass it to logger.WithContextFields.
+func Fields(ctx context.Context) map[string]interface{} {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return nil
+	}
+	return map[string]interface{}{
+		"trace_id": spanContext.TraceID().String(),
+		"span_id":  spanContext.SpanID().String(),
+	}
+}
@@ -0,0 +1,188 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Path of the logs endpoint of an OTLP/HTTP receiver, like the OpenTelemetry Collector's
+const otlpLogsPath = "/v1/logs"
+
+// Scope the log records are reported under
+const otlpScope = "github.com/edgexfoundry/support-logging-client-go"
+
+// OTLP severity numbers of the EdgeX levels, the first of each OTLP range
+var otlpSeverities = map[support_domain.LogLevel]int{
+	support_domain.TRACE: 1,
+	support_domain.DEBUG: 5,
+	support_domain.INFO:  9,
+	support_domain.WARN:  13,
+	support_domain.ERROR: 17,
+}
+
+// Exports log entries as OTLP log records over HTTP, in batches
+type otlpSink struct {
+	url   string
+	batch *logBatch
+}
+
+// Also export log entries to an OpenTelemetry Collector (or other OTLP/HTTP receiver) at
+// endpoint, like "http://localhost:4318", as OTLP log records encoded as JSON. Entries are sent
+// in batches of the size and flush interval set with WithBatching (or 100 entries and 5 seconds),
+// through the same http client, headers and authorization as requests to the logging service.
+func WithOTLP(endpoint string) Option {
+	return func(lc *LoggingClient) error {
+		url := strings.TrimSuffix(endpoint, "/")
+		if !strings.HasSuffix(url, otlpLogsPath) {
+			url += otlpLogsPath
+		}
+		lc.addSink(&otlpSink{url: url, batch: &logBatch{}})
+		return nil
+	}
+}
+
+func (s *otlpSink) send(lc LoggingClient, entry LogEntry) error {
+	return lc.addToSinkBatch(s.batch, entry, s.newPost)
+}
+
+// Export the pending entries now, waiting for the result
+func (s *otlpSink) close(lc LoggingClient) error {
+	return lc.flushSinkBatch(s.batch, s.newPost)
+}
+
+// Build the export request for the entries
+func (s *otlpSink) newPost(lc LoggingClient, entries []LogEntry) (*http.Request, error) {
+	body, err := json.Marshal(otlpRequest(entries))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMarshal, err.Error())
+	}
+	return lc.newPost(s.url, "application/json", body)
+}
+
+// Build an ExportLogsServiceRequest, with a resource for each service (and host and process)
+// the entries come from
+func otlpRequest(entries []LogEntry) map[string]interface{} {
+	var resourceLogs []map[string]interface{}
+	records := map[string]*[]map[string]interface{}{}
+	for _, entry := range entries {
+		key := entry.OriginService + "\x00" + entry.Hostname + "\x00" + strconv.Itoa(entry.Pid)
+		group, ok := records[key]
+		if !ok {
+			group = &[]map[string]interface{}{}
+			records[key] = group
+			resourceLogs = append(resourceLogs, map[string]interface{}{
+				"resource": map[string]interface{}{"attributes": otlpResource(entry)},
+				"scopeLogs": []map[string]interface{}{{
+					"scope":      map[string]interface{}{"name": otlpScope},
+					"logRecords": group,
+				}},
+			})
+		}
+		*group = append(*group, otlpRecord(entry))
+	}
+	return map[string]interface{}{"resourceLogs": resourceLogs}
+}
+
+// Attributes of the resource an entry comes from, named by the OpenTelemetry semantic conventions
+func otlpResource(entry LogEntry) []map[string]interface{} {
+	attributes := []map[string]interface{}{otlpAttribute("service.name", entry.OriginService)}
+	if entry.Hostname != "" {
+		attributes = append(attributes, otlpAttribute("host.name", entry.Hostname))
+	}
+	if entry.Pid != 0 {
+		attributes = append(attributes, otlpAttribute("process.pid", entry.Pid))
+	}
+	return attributes
+}
+
+// Convert an entry into a LogRecord. Labels and fields become attributes, except the
+// trace_id and span_id fields, which identify the span the record belongs to.
+func otlpRecord(entry LogEntry) map[string]interface{} {
+	record := map[string]interface{}{
+		// 64 bit integers are strings in the JSON encoding of OTLP
+		"timeUnixNano":   strconv.FormatInt(entryTime(entry).UnixNano(), 10),
+		"severityNumber": otlpSeverities[entry.Level],
+		"severityText":   string(entry.Level),
+		"body":           otlpValue(entry.Message),
+	}
+
+	var attributes []map[string]interface{}
+	if len(entry.Labels) > 0 {
+		attributes = append(attributes, otlpAttribute("labels", entry.Labels))
+	}
+	for _, key := range sortedKeys(entry.Fields) {
+		value := entry.Fields[key]
+		if id, ok := value.(string); ok && key == "trace_id" {
+			record["traceId"] = id
+		} else if ok && key == "span_id" {
+			record["spanId"] = id
+		} else {
+			attributes = append(attributes, otlpAttribute(key, value))
+		}
+	}
+	if entry.CorrelationID != "" {
+		attributes = append(attributes, otlpAttribute("correlation_id", entry.CorrelationID))
+	}
+	if entry.Stack != "" {
+		attributes = append(attributes, otlpAttribute("exception.stacktrace", entry.Stack))
+	}
+	if len(attributes) > 0 {
+		record["attributes"] = attributes
+	}
+	return record
+}
+
+func otlpAttribute(key string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{"key": key, "value": otlpValue(value)}
+}
+
+// Convert a value into an AnyValue. Types OTLP has no value for are sent as text.
+func otlpValue(value interface{}) map[string]interface{} {
+	switch value := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": value}
+	case bool:
+		return map[string]interface{}{"boolValue": value}
+	case int:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int32:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(value), 10)}
+	case int64:
+		return map[string]interface{}{"intValue": strconv.FormatInt(value, 10)}
+	case uint:
+		return map[string]interface{}{"intValue": strconv.FormatUint(uint64(value), 10)}
+	case float32:
+		return map[string]interface{}{"doubleValue": float64(value)}
+	case float64:
+		return map[string]interface{}{"doubleValue": value}
+	case []string:
+		values := make([]map[string]interface{}, len(value))
+		for i, s := range value {
+			values[i] = otlpValue(s)
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(value)}
+	}
+}
@@ -0,0 +1,40 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+func TestOTLPRecordTime(t *testing.T) {
+	clock := &manualClock{now: time.Date(2017, 11, 20, 10, 0, 0, 123456789, time.UTC)}
+	lc, err := New("test", WithoutConsole(), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The record keeps the nanoseconds that Created, in milliseconds, loses
+	record := otlpRecord(lc.buildLogEntry(support_domain.INFO, "message", nil))
+	if got, want := record["timeUnixNano"], "1511172000123456789"; got != want {
+		t.Errorf("timeUnixNano %v, want %s", got, want)
+	}
+}
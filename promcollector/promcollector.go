@@ -0,0 +1,69 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+// Package promcollector turns the Stats of a logging client into Prometheus counters, of logs
+// per level and of remote sends, labeled with the service name. Register the collector
+// from New with a prometheus.Registerer.
+package promcollector
+
+import (
+	"github.com/edgexfoundry/support-logging-client-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Exposes the counts of logs and remote sends to Prometheus
+type collector struct {
+	lc      logger.LoggingClient
+	logged  *prometheus.Desc
+	sent    *prometheus.Desc
+	failed  *prometheus.Desc
+	dropped *prometheus.Desc
+	skipped *prometheus.Desc
+}
+
+// Get a Prometheus collector for the client's logs per level and the counts of remote sends,
+// to register with a registry. The metrics are labeled with the service name.
+func New(lc logger.LoggingClient, serviceName string) prometheus.Collector {
+	labels := prometheus.Labels{"service": serviceName}
+	return &collector{
+		lc:      lc,
+		logged:  prometheus.NewDesc("edgex_log_entries_total", "Logs passing the log level.", []string{"level"}, labels),
+		sent:    prometheus.NewDesc("edgex_log_remote_sent_total", "Requests accepted by the logging service.", nil, labels),
+		failed:  prometheus.NewDesc("edgex_log_remote_failed_total", "Requests to the logging service that failed, after any retries.", nil, labels),
+		dropped: prometheus.NewDesc("edgex_log_remote_dropped_total", "Requests to the logging service discarded because the send queue was full.", nil, labels),
+		skipped: prometheus.NewDesc("edgex_log_remote_skipped_total", "Requests to the logging service not made because the circuit breaker was open.", nil, labels),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.logged
+	ch <- c.sent
+	ch <- c.failed
+	ch <- c.dropped
+	ch <- c.skipped
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.lc.Stats()
+	for logLevel, count := range stats.Logged {
+		ch <- prometheus.MustNewConstMetric(c.logged, prometheus.CounterValue, float64(count), string(logLevel))
+	}
+	ch <- prometheus.MustNewConstMetric(c.sent, prometheus.CounterValue, float64(stats.Sent))
+	ch <- prometheus.MustNewConstMetric(c.failed, prometheus.CounterValue, float64(stats.Failed))
+	ch <- prometheus.MustNewConstMetric(c.dropped, prometheus.CounterValue, float64(stats.Dropped))
+	ch <- prometheus.MustNewConstMetric(c.skipped, prometheus.CounterValue, float64(stats.Skipped))
+}
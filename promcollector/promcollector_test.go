@@ -0,0 +1,40 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package promcollector
+
+import (
+	"testing"
+
+	"github.com/edgexfoundry/support-logging-client-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestCollector(t *testing.T) {
+	lc := logger.NewNullClient()
+	collector := New(lc, "test")
+
+	descs := make(chan *prometheus.Desc, 10)
+	collector.Describe(descs)
+	metrics := make(chan prometheus.Metric, 10)
+	collector.Collect(metrics)
+
+	// One count per level, and the four counts of sends
+	if len(descs) != 5 || len(metrics) != 9 {
+		t.Errorf("%d descriptions and %d metrics, want 5 and 9", len(descs), len(metrics))
+	}
+}
@@ -0,0 +1,95 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Put these values of each entry in the query string of the url of the logging service, for
+// services that route or partition logs by query parameters. A key is either "service" (the
+// origin service), "level", or the key of labels like "key=value" or "key:value". A batch only
+// gets the parameters all of its entries agree on. Parameters already on the url are kept as they are.
+func WithQueryFromLabels(keys ...string) Option {
+	return func(lc *LoggingClient) error {
+		lc.queryKeys = append(lc.queryKeys, keys...)
+		return nil
+	}
+}
+
+// The url of the logging service with the query parameters taken from the entry or batch of entries
+func (lc LoggingClient) queryUrl(remoteUrl string, payload interface{}) string {
+	if len(lc.queryKeys) == 0 {
+		return remoteUrl
+	}
+
+	var entries []LogEntry
+	switch payload := payload.(type) {
+	case LogEntry:
+		entries = []LogEntry{payload}
+	case []LogEntry:
+		entries = payload
+	}
+	parsed, err := url.Parse(remoteUrl)
+	if err != nil || len(entries) == 0 {
+		return remoteUrl
+	}
+
+	query := parsed.Query()
+	for _, key := range lc.queryKeys {
+		if _, exists := query[key]; exists {
+			continue
+		}
+		if value, ok := commonQueryValue(entries, key); ok {
+			query.Set(key, value)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// The value of the query parameter if every entry has the same one
+func commonQueryValue(entries []LogEntry, key string) (string, bool) {
+	value, ok := queryValue(entries[0], key)
+	for _, entry := range entries[1:] {
+		if !ok {
+			break
+		}
+		next, found := queryValue(entry, key)
+		ok = found && next == value
+	}
+	return value, ok
+}
+
+// The value of the query parameter for the entry, if it has one
+func queryValue(entry LogEntry, key string) (string, bool) {
+	switch key {
+	case "service":
+		return entry.OriginService, true
+	case "level":
+		return string(entry.Level), true
+	}
+
+	for _, label := range entry.Labels {
+		if strings.HasPrefix(label, key+"=") || strings.HasPrefix(label, key+":") {
+			return label[len(key)+1:], true
+		}
+	}
+	return "", false
+}
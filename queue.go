@@ -0,0 +1,227 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"github.com/edgexfoundry/support-domain-go"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// What to do with a remote send when the send queue is full
+type DropPolicy int
+
+const (
+	// Discard the send that didn't fit (the default)
+	DropNewest DropPolicy = iota
+	// Discard the oldest queued send to make room
+	DropOldest
+	// Make the log call wait until there is room
+	BlockWhenFull
+)
+
+// Default bounds on remote sends waiting to go out and sends in progress
+const (
+	defaultQueueSize   = 1000
+	defaultSendWorkers = 4
+)
+
+// Counts of logs and remote sends since the client was created
+type SendStats struct {
+	// Logs passing the log level, per level
+	Logged map[support_domain.LogLevel]uint64
+	// Requests accepted by the logging service
+	Sent uint64
+	// Requests discarded because the send queue was full
+	Dropped uint64
+	// Requests that failed, after any retries
+	Failed uint64
+	// Requests not made because the circuit breaker was open
+	Skipped uint64
+}
+
+type sendStats struct {
+	sent    uint64
+	dropped uint64
+	failed  uint64
+	skipped uint64
+
+	// Logs passing the log level, indexed by the rank of the level in logLevels
+	logged [5]uint64
+}
+
+// Get the counts of logs and remote sends, to monitor how many logs are lost
+func (lc LoggingClient) Stats() SendStats {
+	lc = lc.initialized()
+	logged := make(map[support_domain.LogLevel]uint64, len(logLevels))
+	for logLevel, rank := range logLevels {
+		logged[logLevel] = atomic.LoadUint64(&lc.stats.logged[rank])
+	}
+	return SendStats{
+		Logged:  logged,
+		Sent:    atomic.LoadUint64(&lc.stats.sent),
+		Dropped: atomic.LoadUint64(&lc.stats.dropped),
+		Failed:  atomic.LoadUint64(&lc.stats.failed),
+		Skipped: atomic.LoadUint64(&lc.stats.skipped),
+	}
+}
+
+// A remote send waiting in the queue, made with the settings of the client that logged it
+type queuedSend struct {
+	lc      LoggingClient
+	ctx     context.Context
+	request *http.Request
+	// The entry or entries in the request, nil if the request isn't to the logging service
+	payload interface{}
+	// Called with the outcome of the send, if set
+	done func(error)
+	// Made instead of the request, for posts elsewhere than the logging service like alerts,
+	// which don't go through its circuit breaker or spool
+	post func() error
+}
+
+// Report the outcome of the send
+func (item queuedSend) finish(err error) {
+	if item.done != nil {
+		item.done(err)
+	}
+	item.lc.sends.finish()
+}
+
+// Bounded queue of remote sends, worked through by a fixed number of goroutines
+// rather than one goroutine per log. The workers are started by the first push and
+// stopped by stop, after which sends are refused.
+type sendQueue struct {
+	items   chan queuedSend
+	workers int
+	policy  DropPolicy
+	stats   *sendStats
+	start   sync.Once
+	running sync.WaitGroup
+
+	// Held for reading while pushing, so the channel isn't closed under a push
+	mutex  sync.RWMutex
+	closed bool
+}
+
+func newSendQueue(size int, workers int, policy DropPolicy, stats *sendStats) *sendQueue {
+	return &sendQueue{
+		items:   make(chan queuedSend, size),
+		workers: workers,
+		policy:  policy,
+		stats:   stats,
+	}
+}
+
+// Queue a send, applying the drop policy if the queue is full. Returns ErrDropped
+// if it was the new send that was dropped, or ErrClosed once the queue is stopped.
+func (q *sendQueue) push(item queuedSend) error {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+	if q.closed {
+		return ErrClosed
+	}
+
+	// Nothing runs until the first remote log
+	q.start.Do(q.startWorkers)
+
+	switch q.policy {
+	case BlockWhenFull:
+		q.items <- item
+		return nil
+
+	case DropOldest:
+		for {
+			select {
+			case q.items <- item:
+				return nil
+			default:
+			}
+
+			select {
+			case oldest := <-q.items:
+				q.drop(oldest)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case q.items <- item:
+			return nil
+		default:
+			atomic.AddUint64(&q.stats.dropped, 1)
+			return ErrDropped
+		}
+	}
+}
+
+// Discard a queued send
+func (q *sendQueue) drop(item queuedSend) {
+	atomic.AddUint64(&q.stats.dropped, 1)
+	item.finish(ErrDropped)
+}
+
+func (q *sendQueue) startWorkers() {
+	q.running.Add(q.workers)
+	for i := 0; i < q.workers; i++ {
+		go q.work()
+	}
+}
+
+// Refuse further sends and let the workers exit once the queued sends are made
+func (q *sendQueue) stop() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.items)
+	}
+}
+
+// Wait for the workers to exit after stop, returning false if they are still busy after timeout
+func (q *sendQueue) join(timeout time.Duration) bool {
+	stopped := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (q *sendQueue) work() {
+	defer q.running.Done()
+	for item := range q.items {
+		err := item.ctx.Err()
+		if err == nil && item.post != nil {
+			err = item.post()
+		} else if err == nil {
+			err = item.lc.makeRequest(item.request, item.payload)
+		}
+		item.finish(err)
+	}
+}
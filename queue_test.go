@@ -0,0 +1,78 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSendOrderWithOneWorker(t *testing.T) {
+	server := newCollectingServer(t)
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithSendQueue(100, 1, BlockWhenFull))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const logs = 50
+	for i := 0; i < logs; i++ {
+		lc.Info(fmt.Sprintf("message %d", i))
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	received := server.received()
+	if len(received) != logs {
+		t.Fatalf("%d entries received, want %d", len(received), logs)
+	}
+	for i, msg := range received {
+		if want := fmt.Sprintf("message %d", i); msg != want {
+			t.Fatalf("entry %d is %q, want %q", i, msg, want)
+		}
+	}
+}
+
+func TestNoGoroutinesLeftAfterClose(t *testing.T) {
+	server := newCollectingServer(t)
+	before := runtime.NumGoroutine()
+
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithBatching(10, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		lc.Info("message")
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Goroutines that are done may take a moment to exit
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		buf := make([]byte, 64*1024)
+		t.Fatalf("%d goroutines before New, %d after Close:\n%s", before, after, buf[:runtime.Stack(buf, true)])
+	}
+}
@@ -0,0 +1,60 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+// Log a panic as an ERROR with its stack trace, then panic again so the program still crashes.
+// Use it at the top of a goroutine with defer lc.Recover(). Any batched entries are sent first,
+// and the entry is sent to the logging service before Recover returns.
+func (lc LoggingClient) Recover() {
+	if value := recover(); value != nil {
+		lc.logPanic(value)
+		panic(value)
+	}
+}
+
+// Log a panic as an ERROR with its stack trace and carry on, for goroutines that are
+// restarted by a supervisor. Use it with defer lc.RecoverAndContinue().
+func (lc LoggingClient) RecoverAndContinue() {
+	if value := recover(); value != nil {
+		lc.logPanic(value)
+	}
+}
+
+// Log the panic, waiting for the logging service. It is never sampled or batched away.
+// What was batched before goes first, so the entries stay in order.
+func (lc LoggingClient) logPanic(value interface{}) {
+	if err := lc.Flush(); err != nil {
+		lc.reportError(err)
+	}
+
+	lc.Synchronous = true
+	lc.BatchSize = 0
+	lc.sampler = nil
+	lc.deduplicator = nil
+	// The stack is still that of the panicking code while deferred functions run
+	lc.stackLevel = support_domain.TRACE
+	if err := lc.logWithContext(context.Background(), support_domain.ERROR, fmt.Sprintf("panic: %v", value), []string{"panic"}); err != nil {
+		lc.reportError(err)
+	}
+}
@@ -0,0 +1,44 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRecoverSendsBatchFirst(t *testing.T) {
+	server := newCollectingServer(t)
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithBatching(10, time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lc.Info("before the panic")
+	func() {
+		defer lc.RecoverAndContinue()
+		panic("failed")
+	}()
+
+	want := []string{"before the panic", "panic: failed"}
+	if got := server.received(); !reflect.DeepEqual(got, want) {
+		t.Errorf("received %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"regexp"
+)
+
+// Replaces whatever matched a redaction pattern
+const redactedText = "***"
+
+// Patterns for secrets that must never leave the client
+type redactor struct {
+	patterns []*regexp.Regexp
+}
+
+func newRedactor(patterns []string) (*redactor, error) {
+	r := &redactor{}
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Mask every match in the labels and string field values, and the whole value of any field
+// whose key matches. The labels and fields are copied, as they may be shared. The message
+// is masked when logged, before it may be truncated or split.
+func (r *redactor) redact(entry *LogEntry) {
+	if len(entry.Labels) > 0 {
+		labels := make([]string, len(entry.Labels))
+		for i, label := range entry.Labels {
+			labels[i] = r.redactString(label)
+		}
+		entry.Labels = labels
+	}
+
+	if len(entry.Fields) > 0 {
+		fields := make(map[string]interface{}, len(entry.Fields))
+		for key, value := range entry.Fields {
+			if r.matchesKey(key) {
+				fields[key] = redactedText
+			} else if s, ok := value.(string); ok {
+				fields[key] = r.redactString(s)
+			} else {
+				fields[key] = value
+			}
+		}
+		entry.Fields = fields
+	}
+}
+
+func (r *redactor) redactString(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, redactedText)
+	}
+	return s
+}
+
+// Whether the pattern matches all of the key, so "password" masks a password field but not "password_set"
+func (r *redactor) matchesKey(key string) bool {
+	for _, re := range r.patterns {
+		if loc := re.FindStringIndex(key); loc != nil && loc[0] == 0 && loc[1] == len(key) {
+			return true
+		}
+	}
+	return false
+}
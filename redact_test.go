@@ -0,0 +1,92 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRedactionInEveryOutput(t *testing.T) {
+	const token = "tok_4f9a8b7c6d5e"
+	server := newCollectingServer(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	var stdout bytes.Buffer
+	lc, err := New("test", WithWriter(&stdout), WithLogFile(path), WithRemote(server.URL), WithSynchronous(), WithRedaction(`tok_[0-9a-f]+`, "password"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	memory := &MemorySink{}
+	lc.AddHook(memory)
+
+	lc.WithFields(map[string]interface{}{"password": "hunter2", "request": "auth " + token}).Info("login with "+token, "token:"+token)
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string]string{
+		"stdout": stdout.String(),
+		"file":   readFile(t, path),
+		"remote": strings.Join(server.requests(), "\n"),
+		"hook":   memory.LastMessage(),
+	}
+	for name, output := range outputs {
+		if strings.Contains(output, token) || strings.Contains(output, "hunter2") {
+			t.Errorf("secret not masked in %s: %s", name, output)
+		}
+		if !strings.Contains(output, "login with ***") {
+			t.Errorf("message not in %s: %s", name, output)
+		}
+	}
+
+	entry := memory.Entries()[0]
+	if entry.Labels[0] != "token:***" || entry.Fields["password"] != "***" || entry.Fields["request"] != "auth ***" {
+		t.Errorf("labels %v and fields %v not masked", entry.Labels, entry.Fields)
+	}
+}
+
+func TestRedactionBeforeCut(t *testing.T) {
+	const token = "tok_4f9a8b7c6d5e"
+	for _, split := range []bool{false, true} {
+		memory := &MemorySink{}
+		lc, err := New("test", WithoutConsole(), WithMaxMessageSize(20, split), WithRedaction(`tok_[0-9a-f]{12}`, `^.{40,}$`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		lc.AddHook(memory)
+
+		// The second pattern only matches the whole message
+		lc.Info("the request had a token " + token + " in its header")
+		for _, entry := range memory.Entries() {
+			if entry.Message != "***" {
+				t.Errorf("split %t: logged %q, want the whole message masked", split, entry.Message)
+			}
+		}
+
+		// The token would straddle the cut
+		memory.Reset()
+		lc.Info("sixteen chars: " + token)
+		for _, entry := range memory.Entries() {
+			if strings.Contains(entry.Message, "tok_") || strings.Contains(entry.Message, "4f9a") {
+				t.Errorf("split %t: logged %q with part of the token", split, entry.Message)
+			}
+		}
+	}
+}
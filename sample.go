@@ -0,0 +1,97 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"github.com/edgexfoundry/support-domain-go"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Rate limits identical messages so an error loop can't flood the log file and logging service
+type sampler struct {
+	mutex      sync.Mutex
+	initial    int
+	thereafter int
+	interval   time.Duration
+	counts     map[uint64]*sampleCount
+	timer      *time.Timer
+}
+
+// Occurrences of one level and message during the current interval
+type sampleCount struct {
+	level      support_domain.LogLevel
+	message    string
+	seen       int
+	suppressed int
+}
+
+func newSampler(initial int, thereafter int, interval time.Duration) *sampler {
+	return &sampler{
+		initial:    initial,
+		thereafter: thereafter,
+		interval:   interval,
+		counts:     map[uint64]*sampleCount{},
+	}
+}
+
+// Whether this occurrence of the message should be logged. The first initial occurrences
+// in an interval are, after that only every thereafter-th one (none if thereafter is 0).
+func (s *sampler) allow(lc LoggingClient, logLevel support_domain.LogLevel, msg string) bool {
+	hash := fnv.New64a()
+	hash.Write([]byte(logLevel))
+	hash.Write([]byte{0})
+	hash.Write([]byte(msg))
+	key := hash.Sum64()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.interval, func() {
+			s.endInterval(lc)
+		})
+	}
+
+	count, ok := s.counts[key]
+	if !ok {
+		count = &sampleCount{level: logLevel, message: msg}
+		s.counts[key] = count
+	}
+	count.seen++
+
+	if count.seen <= s.initial || (s.thereafter > 0 && (count.seen-s.initial)%s.thereafter == 0) {
+		return true
+	}
+	count.suppressed++
+	return false
+}
+
+// Start counting again, logging a summary for every message that was suppressed
+func (s *sampler) endInterval(lc LoggingClient) {
+	s.mutex.Lock()
+	counts := s.counts
+	s.counts = map[uint64]*sampleCount{}
+	s.timer = nil
+	s.mutex.Unlock()
+
+	for _, count := range counts {
+		lc.logRepeats(count.level, count.message, count.suppressed)
+	}
+}
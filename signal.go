@@ -0,0 +1,72 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// Watches for the signals of WithSignalFlush until a signal arrives or the client is closed
+type signalWatch struct {
+	signals chan os.Signal
+	done    chan struct{}
+	once    sync.Once
+}
+
+// Stop watching, leaving the signals to the application's own handlers, or their default
+// handling if it has none
+func (w *signalWatch) stop() {
+	w.once.Do(func() {
+		signal.Stop(w.signals)
+		close(w.done)
+	})
+}
+
+// On SIGTERM or SIGINT, flush and close the client then let the signal terminate the process
+func (lc *LoggingClient) flushOnSignal() {
+	w := &signalWatch{signals: make(chan os.Signal, 1), done: make(chan struct{})}
+	signal.Notify(w.signals, syscall.SIGTERM, syscall.SIGINT)
+	lc.signalWatch = w
+
+	client := *lc
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-w.signals:
+		case <-w.done:
+			return
+		}
+		// Stops watching as well, so the signal raised again below isn't caught here
+		if err := client.Close(); err != nil {
+			client.reportError(err)
+		}
+
+		// Raise the signal again so the process ends the way it would have without this
+		// client, or the application's own handlers get it
+		if process, err := os.FindProcess(os.Getpid()); err == nil {
+			if err := process.Signal(sig); err == nil {
+				return
+			}
+		}
+		// The signal can't be raised on this platform, end the process with the conventional code
+		os.Exit(128 + int(sig.(syscall.Signal)))
+	}()
+}
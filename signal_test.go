@@ -0,0 +1,88 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+
+package logger
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCloseStopsWatchingSignals(t *testing.T) {
+	// The first Notify starts the runtime's own signal goroutine, which stays
+	warmUp := make(chan os.Signal, 1)
+	signal.Notify(warmUp, syscall.SIGTERM)
+	signal.Stop(warmUp)
+	before := runtime.NumGoroutine()
+
+	lc, err := New("test", WithWriter(io.Discard), WithSignalFlush())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		buf := make([]byte, 64*1024)
+		t.Fatalf("%d goroutines before New, %d after Close:\n%s", before, after, buf[:runtime.Stack(buf, true)])
+	}
+}
+
+func TestSignalFlushKeepsApplicationHandlers(t *testing.T) {
+	server := newCollectingServer(t)
+
+	// With a handler of its own, the process isn't ended by the signal
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithBatching(10, time.Hour), WithSignalFlush())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lc.Info("message")
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-signals:
+	case <-time.After(time.Second):
+		t.Fatal("the application's handler didn't get the signal")
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(server.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := server.received(); len(got) != 1 || got[0] != "message" {
+		t.Errorf("sent %q on SIGTERM, want the batched message", got)
+	}
+}
@@ -0,0 +1,58 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+// An output of its own for log entries, like those of the mqttsink and grpcsink packages.
+// Add one with WithSink.
+type Sink interface {
+	// Called with each entry for the remote transports, from the goroutine that logged, so
+	// it shouldn't block. An error goes to the error handler.
+	Send(entry LogEntry) error
+	// Send what is still buffered and let go of connections, called by Close
+	Close() error
+}
+
+// Implemented by sinks that can fail in the background, like a stream that breaks. Before
+// the first entry, the client hands them a function passing errors on to its error handler.
+type ErrorReportingSink interface {
+	Sink
+	SetErrorReporter(report func(error))
+}
+
+// Also send every log entry to the sink. The remote level and label filter apply to it as
+// they do to the logging service, and it is left out when logging is local only. A sink
+// implementing fmt.Stringer shows up in Describe.
+func WithSink(sink Sink) Option {
+	return func(lc *LoggingClient) error {
+		lc.addSink(customSink{sink})
+		return nil
+	}
+}
+
+// Adapts a Sink to the outputs built into the client
+type customSink struct {
+	Sink
+}
+
+func (s customSink) send(lc LoggingClient, entry LogEntry) error {
+	return s.Send(entry)
+}
+
+func (s customSink) close(lc LoggingClient) error {
+	return s.Close()
+}
@@ -0,0 +1,93 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+// Sink keeping the messages sent to it
+type recordingSink struct {
+	mutex    sync.Mutex
+	messages []string
+	closed   bool
+	report   func(error)
+}
+
+func (s *recordingSink) Send(entry LogEntry) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.messages = append(s.messages, entry.Message)
+	if entry.Level == support_domain.ERROR {
+		return errors.New("send failed")
+	}
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (s *recordingSink) SetErrorReporter(report func(error)) {
+	s.report = report
+}
+
+func (s *recordingSink) String() string {
+	return "recording=true"
+}
+
+func TestSink(t *testing.T) {
+	sink := &recordingSink{}
+	var reported []error
+	lc, err := New("test", WithWriter(io.Discard), WithSink(sink), WithRemoteLevel(support_domain.INFO),
+		WithRemoteLabelFilter(nil, []string{"internal"}),
+		WithErrorHandler(func(err error) { reported = append(reported, err) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(lc.Describe(), "recording=true") {
+		t.Errorf("sink missing from %s", lc.Describe())
+	}
+
+	lc.Debug("below the remote level")
+	lc.Info("excluded", "internal")
+	lc.Info("sent")
+	lc.Error("failed")
+	sink.report(errors.New("stream broke"))
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"sent", "failed"}; !reflect.DeepEqual(sink.messages, want) {
+		t.Errorf("sink got %q, want %q", sink.messages, want)
+	}
+	if len(reported) != 2 || reported[0].Error() != "send failed" || reported[1].Error() != "stream broke" {
+		t.Errorf("error handler got %v", reported)
+	}
+	if !sink.closed {
+		t.Error("sink not closed")
+	}
+}
@@ -0,0 +1,115 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"github.com/edgexfoundry/support-domain-go"
+	"log/slog"
+)
+
+// Get a slog.Handler that logs through the client, so services can use log/slog:
+//
+//	slog.New(logger.NewSlogHandler(lc))
+//
+// Attributes become fields of the log entry. Attributes inside groups get keys
+// qualified by the group names, like "request.method".
+func NewSlogHandler(lc LoggingClient) slog.Handler {
+	return &slogHandler{lc: lc.initialized()}
+}
+
+type slogHandler struct {
+	lc     LoggingClient
+	fields map[string]interface{}
+	prefix string
+}
+
+// Map a slog level onto the closest support-domain level
+func slogLogLevel(level slog.Level) support_domain.LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return support_domain.TRACE
+	case level < slog.LevelInfo:
+		return support_domain.DEBUG
+	case level < slog.LevelWarn:
+		return support_domain.INFO
+	case level < slog.LevelError:
+		return support_domain.WARN
+	default:
+		return support_domain.ERROR
+	}
+}
+
+func (h *slogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.lc.isLoggable(slogLogLevel(level))
+}
+
+func (h *slogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := h.copyFields(record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, attr)
+		return true
+	})
+
+	return h.lc.WithFields(fields).logWithContext(ctx, slogLogLevel(record.Level), record.Message, nil)
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := h.copyFields(len(attrs))
+	for _, attr := range attrs {
+		addSlogAttr(fields, h.prefix, attr)
+	}
+	return &slogHandler{lc: h.lc, fields: fields, prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{lc: h.lc, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// Copy the fields collected so far, leaving room for extra more
+func (h *slogHandler) copyFields(extra int) map[string]interface{} {
+	fields := make(map[string]interface{}, len(h.fields)+extra)
+	for key, value := range h.fields {
+		fields[key] = value
+	}
+	return fields
+}
+
+// Add the attribute to the fields, following the rules of slog.Handler:
+// empty attributes are ignored and groups without a key are inlined
+func addSlogAttr(fields map[string]interface{}, prefix string, attr slog.Attr) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+		for _, groupAttr := range attr.Value.Group() {
+			addSlogAttr(fields, prefix, groupAttr)
+		}
+		return
+	}
+
+	fields[prefix+attr.Key] = attr.Value.Any()
+}
@@ -0,0 +1,232 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Extension of the files holding spooled requests
+const spoolExt = ".spool"
+
+// Requests that failed, kept on disk until the logging service is reachable again.
+// Each request is a file, named so that sorting the names orders them oldest first.
+type spool struct {
+	mutex    sync.Mutex
+	dir      string
+	maxSize  int64
+	files    []spoolFile
+	size     int64
+	seq      uint64
+	draining bool
+	// Set by Close, after which nothing more is drained
+	closed bool
+}
+
+type spoolFile struct {
+	name string
+	size int64
+}
+
+// A request as saved to the spool. Headers like authorization are added again when it is sent.
+type spooledRequest struct {
+	Url             string `json:"url"`
+	ContentType     string `json:"contentType"`
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	Body            []byte `json:"body"`
+}
+
+// Open the spool in dir, picking up the requests left by a previous run
+func openSpool(dir string, maxSize int64) (*spool, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	infos, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &spool{dir: dir, maxSize: maxSize}
+	for _, entry := range infos {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), spoolExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		s.files = append(s.files, spoolFile{name: entry.Name(), size: info.Size()})
+		s.size += info.Size()
+	}
+	sort.Slice(s.files, func(i, j int) bool {
+		return s.files[i].name < s.files[j].name
+	})
+	return s, nil
+}
+
+// Save the request to send later, dropping the oldest requests to stay within maxSize.
+// A request the caller gave up on isn't saved, it would be sent after all.
+func (s *spool) save(request *http.Request) error {
+	if request.Context().Err() != nil {
+		return nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	record, err := json.Marshal(spooledRequest{
+		Url:             request.URL.String(),
+		ContentType:     request.Header.Get("Content-Type"),
+		ContentEncoding: request.Header.Get("Content-Encoding"),
+		Body:            data,
+	})
+	if err != nil {
+		return err
+	}
+	if int64(len(record)) > s.maxSize {
+		return fmt.Errorf("request of %d bytes is too big for the spool, dropped", len(record))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for len(s.files) > 0 && s.size+int64(len(record)) > s.maxSize {
+		s.remove(s.files[0].name)
+	}
+
+	// Written under another name first, so a crash never leaves half a request to send
+	s.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), s.seq%1000000, spoolExt)
+	tmp := filepath.Join(s.dir, name+".tmp")
+	if err := os.WriteFile(tmp, record, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(s.dir, name)); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	s.files = append(s.files, spoolFile{name: name, size: int64(len(record))})
+	s.size += int64(len(record))
+	return nil
+}
+
+// Remove a spooled request. Must be called with the mutex held.
+func (s *spool) remove(name string) {
+	for i, file := range s.files {
+		if file.name == name {
+			os.Remove(filepath.Join(s.dir, name))
+			s.files = append(s.files[:i], s.files[i+1:]...)
+			s.size -= file.size
+			return
+		}
+	}
+}
+
+// Send the spooled requests in the background, oldest first, stopping at the first failure.
+// The drain counts as a send in flight, so Close waits for the request it is sending.
+func (s *spool) drain(lc LoggingClient) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.draining || s.closed || len(s.files) == 0 {
+		return
+	}
+	s.draining = true
+
+	lc.sends.start()
+	go func() {
+		defer lc.sends.finish()
+		for {
+			s.mutex.Lock()
+			if len(s.files) == 0 || s.closed {
+				s.draining = false
+				s.mutex.Unlock()
+				return
+			}
+			name := s.files[0].name
+			s.mutex.Unlock()
+
+			err := s.send(lc, name)
+
+			s.mutex.Lock()
+			if err != nil && !isRejected(err) {
+				// Still unreachable, the next successful send drains again
+				s.draining = false
+				s.mutex.Unlock()
+				return
+			}
+			// Sent, or rejected and never going to be accepted
+			s.remove(name)
+			s.mutex.Unlock()
+		}
+	}()
+}
+
+// Stop draining after the request being sent, leaving the rest for the next run
+func (s *spool) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closed = true
+}
+
+// Send one spooled request
+func (s *spool) send(lc LoggingClient, name string) error {
+	record, err := os.ReadFile(filepath.Join(s.dir, name))
+	if err != nil {
+		// Dropped to make room for newer requests meanwhile
+		return nil
+	}
+
+	var spooled spooledRequest
+	if err := json.Unmarshal(record, &spooled); err != nil {
+		// Not something that can ever be sent
+		lc.reportError(fmt.Errorf("dropping unreadable spooled request %s: %s", name, err.Error()))
+		return nil
+	}
+
+	req, err := http.NewRequest("POST", spooled.Url, bytes.NewBuffer(spooled.Body))
+	if err != nil {
+		return nil
+	}
+	req.Header.Add("Content-Type", spooled.ContentType)
+	if spooled.ContentEncoding != "" {
+		req.Header.Add("Content-Encoding", spooled.ContentEncoding)
+	}
+	lc.addHeaders(req)
+
+	return lc.deliver(req)
+}
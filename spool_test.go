@@ -0,0 +1,112 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Spool requests to url in dir, while the logging service there is down
+func spoolRequests(t *testing.T, url string, dir string, requests int) {
+	t.Helper()
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(url), WithSynchronous(), WithSpool(dir, 1<<20),
+		WithErrorHandler(func(error) {}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < requests; i++ {
+		lc.Info("spooled")
+	}
+	lc.Close()
+}
+
+func spooledFiles(t *testing.T, dir string) int {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+spoolExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(files)
+}
+
+func TestCloseStopsDrainingSpool(t *testing.T) {
+	var up, received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&up) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		atomic.AddInt32(&received, 1)
+		time.Sleep(50 * time.Millisecond)
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+	spoolRequests(t, server.URL, dir, 5)
+	atomic.StoreInt32(&up, 1)
+
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithSpool(dir, 1<<20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt32(&received) == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing is sent once Close returned, and what wasn't sent is kept for the next run
+	sent := atomic.LoadInt32(&received)
+	time.Sleep(200 * time.Millisecond)
+	if after := atomic.LoadInt32(&received); after != sent || sent == 0 || sent == 5 {
+		t.Errorf("%d spooled requests sent before Close returned and %d after, want some of 5", sent, after-sent)
+	}
+	if left := spooledFiles(t, dir); left != 5-int(sent) {
+		t.Errorf("%d requests left in the spool, want %d", left, 5-sent)
+	}
+}
+
+func TestCanceledRequestNotSpooled(t *testing.T) {
+	dir := t.TempDir()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+	lc, err := New("test", WithWriter(io.Discard), WithRemote(server.URL), WithSynchronous(), WithSpool(dir, 1<<20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lc.Close()
+
+	// The caller gives up while the logging service is still answering
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := lc.InfoCtx(ctx, "abandoned"); err == nil {
+		t.Fatal("send outlived its context")
+	}
+	if spooled := spooledFiles(t, dir); spooled != 0 {
+		t.Errorf("%d requests spooled", spooled)
+	}
+}
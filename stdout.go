@@ -0,0 +1,87 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bufio"
+	"github.com/edgexfoundry/support-domain-go"
+	"time"
+)
+
+// How soon lines buffered with WithStdOutBuffer are written out
+const stdOutFlushInterval = 200 * time.Millisecond
+
+// Buffers the lines printed to stdout, guarded by the client's mutex
+type stdOutBuffer struct {
+	writer *bufio.Writer
+	timer  *time.Timer
+}
+
+// Write out what is buffered
+func (b *stdOutBuffer) flush() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	b.writer.Flush()
+}
+
+// Buffer up to size bytes of lines printed to stdout, writing them out within a fraction of
+// a second, or when the buffer is full. ERROR lines are written out immediately, and everything
+// on Close. This saves a write per line when stdout is a pipe, as in containers; leave it off
+// for interactive use.
+func WithStdOutBuffer(size int) Option {
+	return func(lc *LoggingClient) error {
+		lc.stdOutBufferSize = size
+		return nil
+	}
+}
+
+// Write out buffered stdout lines after a line of the level was printed: straight away for
+// errors, or else soon. Must be called with the mutex held.
+func (lc LoggingClient) flushStdOut(logLevel support_domain.LogLevel) {
+	b := lc.stdOutBuffer
+	if b == nil {
+		return
+	}
+
+	if logLevel == support_domain.ERROR {
+		b.flush()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(stdOutFlushInterval, func() {
+			lc.mutex.Lock()
+			defer lc.mutex.Unlock()
+			b.timer = nil
+			b.flush()
+		})
+	}
+}
+
+// Print a line about the client itself, like a failed send or logging recovering from an outage,
+// to stdout along with the logs, or to stderr for warnings with WithStderrForErrors. Not printed
+// when stdout is off. Must not be called with the mutex held.
+func (lc LoggingClient) printNotice(logLevel support_domain.LogLevel, msg string) {
+	if lc.discard || !lc.EnableStdOut || lc.mutex == nil {
+		return
+	}
+
+	lc.mutex.Lock()
+	defer lc.mutex.Unlock()
+	lc.consoleLogger(logLevel).Println(msg)
+	lc.flushStdOut(logLevel)
+}
@@ -0,0 +1,233 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// How often streamed lines are flushed when WithNDJSONStream isn't given an interval
+const defaultStreamFlushInterval = time.Second
+
+// Longest wait between attempts to open the stream again
+const streamMaxBackoff = 30 * time.Second
+
+// The logging service ended the stream by answering, so a new one is opened straight away
+var errStreamEnded = errors.New("stream ended by the logging service")
+
+// Streams log entries as JSON lines in the body of one long lived (chunked) POST
+type ndjsonSink struct {
+	url      string
+	interval time.Duration
+	entries  chan LogEntry
+	done     chan struct{}
+	stopped  chan struct{}
+	start    sync.Once
+	stop     sync.Once
+}
+
+// Also stream log entries to the endpoint at url as newline delimited JSON, one entry per line,
+// in the body of a single request that is kept open, instead of a request per entry or batch.
+// Lines are flushed at least every interval (a second if interval isn't positive). The request
+// is made on the first log and again whenever it ends or fails, with the headers and authorization
+// of the logging service. If the stream can't keep up, entries are dropped and counted in Stats.
+func WithNDJSONStream(url string, interval time.Duration) Option {
+	return func(lc *LoggingClient) error {
+		if interval <= 0 {
+			interval = defaultStreamFlushInterval
+		}
+		lc.addSink(&ndjsonSink{
+			url:      url,
+			interval: interval,
+			entries:  make(chan LogEntry, defaultQueueSize),
+			done:     make(chan struct{}),
+			stopped:  make(chan struct{}),
+		})
+		return nil
+	}
+}
+
+func (s *ndjsonSink) send(lc LoggingClient, entry LogEntry) error {
+	s.start.Do(func() {
+		go s.run(lc)
+	})
+
+	select {
+	case s.entries <- entry:
+	default:
+		atomic.AddUint64(&lc.stats.dropped, 1)
+	}
+	return nil
+}
+
+// Send what is still buffered and end the request, waiting (for a bounded time) for the answer
+func (s *ndjsonSink) close(lc LoggingClient) error {
+	// Nothing to wait for if nothing was ever logged
+	s.start.Do(func() {
+		close(s.stopped)
+	})
+	s.stop.Do(func() {
+		close(s.done)
+	})
+
+	select {
+	case <-s.stopped:
+		return nil
+	case <-time.After(closeTimeout):
+		return fmt.Errorf("timed out closing the log stream to %s", s.url)
+	}
+}
+
+// Keep a stream open for as long as the client is, opening it again when it ends or fails
+func (s *ndjsonSink) run(lc LoggingClient) {
+	defer close(s.stopped)
+
+	var pending *LogEntry
+	backoff := time.Second
+	for {
+		// Only open a stream once there is something to write, not to keep an idle one open
+		if pending == nil {
+			select {
+			case entry := <-s.entries:
+				pending = &entry
+			case <-s.done:
+				return
+			}
+		}
+
+		var err error
+		if pending, err = s.stream(lc, pending); err == nil {
+			return
+		}
+
+		// The service may close streams now and then, which is no reason to wait
+		if err == errStreamEnded {
+			backoff = time.Second
+			continue
+		}
+		lc.reportError(fmt.Errorf("log stream to %s failed: %s", s.url, err.Error()))
+
+		select {
+		case <-time.After(backoff):
+		case <-s.done:
+			// One last try for the entries still buffered
+			if _, err := s.stream(lc, pending); err != nil {
+				lc.reportError(fmt.Errorf("log stream to %s failed: %s", s.url, err.Error()))
+			}
+			return
+		}
+		if backoff *= 2; backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+// Write entries to one request until the sink is closed. Returns the entry being written if the
+// request fails or ends, to write first on the next one. Lines still buffered then are lost.
+func (s *ndjsonSink) stream(lc LoggingClient, pending *LogEntry) (*LogEntry, error) {
+	reader, writer := io.Pipe()
+	req, err := http.NewRequest("POST", s.url, reader)
+	if err != nil {
+		return pending, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	lc.addHeaders(req)
+
+	// The answer only comes once the body ends, or when the service gives up on the stream
+	result := make(chan error, 1)
+	go func() {
+		err := s.do(lc, req)
+		reader.CloseWithError(err)
+		result <- err
+	}()
+
+	buffer := bufio.NewWriter(writer)
+	write := func(entry LogEntry) error {
+		line, err := lc.marshalPayload(entry)
+		if err != nil {
+			// Only the fields can fail to encode, keep the rest of the entry
+			line = []byte(formatJSON(entry))
+		}
+		_, err = buffer.Write(append(line, '\n'))
+		return err
+	}
+
+	if pending != nil {
+		if write(*pending) != nil {
+			return pending, <-result
+		}
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case entry := <-s.entries:
+			if write(entry) != nil {
+				return &entry, <-result
+			}
+		case <-ticker.C:
+			if buffer.Flush() != nil {
+				return nil, <-result
+			}
+		case err := <-result:
+			return nil, err
+		case <-s.done:
+			// Write what is still buffered, then end the body
+			for len(s.entries) > 0 {
+				entry := <-s.entries
+				if write(entry) != nil {
+					return &entry, <-result
+				}
+			}
+			if buffer.Flush() != nil {
+				return nil, <-result
+			}
+			writer.Close()
+
+			if err := <-result; err != errStreamEnded {
+				return nil, err
+			}
+			return nil, nil
+		}
+	}
+}
+
+// Make the streaming request, returning errStreamEnded if it was accepted.
+// There is no RemoteTimeout, the request lasts as long as the stream.
+func (s *ndjsonSink) do(lc LoggingClient, req *http.Request) error {
+	resp, err := lc.httpClient.Do(req)
+	if err != nil {
+		return &sendError{err: err}
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &RemoteStatusError{URL: s.url, StatusCode: resp.StatusCode, Status: resp.Status, Body: strings.TrimSpace(string(body))}
+	}
+	return errStreamEnded
+}
@@ -0,0 +1,71 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"github.com/edgexfoundry/support-domain-go"
+	"log/syslog"
+)
+
+// Send logs to syslog, either the local daemon (when network and raddr are empty)
+// or a remote one, tagging them with tag. Each level is logged with the matching severity.
+// A remote daemon is left out when EDGEX_LOG_LOCAL_ONLY is set.
+func WithSyslog(network string, raddr string, tag string) Option {
+	return func(lc *LoggingClient) error {
+		if lc.localOnly && isRemoteSyslog(network) {
+			return nil
+		}
+		writer, err := syslog.Dial(network, raddr, syslog.LOG_USER|syslog.LOG_INFO, tag)
+		if err != nil {
+			return err
+		}
+
+		lc.syslog = syslogWriter{writer: writer}
+		return nil
+	}
+}
+
+// Whether the network reaches a syslog daemon on another machine, rather than the local
+// daemon (an empty network) or a unix socket
+func isRemoteSyslog(network string) bool {
+	return network != "" && network != "unix" && network != "unixgram"
+}
+
+type syslogWriter struct {
+	writer *syslog.Writer
+}
+
+func (s syslogWriter) write(logLevel support_domain.LogLevel, msg string) error {
+	switch logLevel {
+	case support_domain.TRACE, support_domain.DEBUG:
+		return s.writer.Debug(msg)
+	case support_domain.INFO:
+		return s.writer.Info(msg)
+	case support_domain.WARN:
+		return s.writer.Warning(msg)
+	default:
+		return s.writer.Err(msg)
+	}
+}
+
+func (s syslogWriter) close() error {
+	return s.writer.Close()
+}
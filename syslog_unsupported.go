@@ -0,0 +1,33 @@
+//go:build windows || plan9
+// +build windows plan9
+
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"errors"
+	"runtime"
+)
+
+// Syslog isn't available on this platform, so creating a client with this option fails
+func WithSyslog(network string, raddr string, tag string) Option {
+	return func(lc *LoggingClient) error {
+		return errors.New("syslog is not supported on " + runtime.GOOS)
+	}
+}
@@ -0,0 +1,95 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"fmt"
+	"github.com/edgexfoundry/support-domain-go"
+	"strings"
+)
+
+// Log an INFO level message rendered from a template like "device {deviceName} returned {status}",
+// whose placeholders are replaced by the values of fields. The fields are also attached to the
+// entry, so the values can be queried. A placeholder without a value is left as it is and named
+// in a templateWarning field.
+func (lc LoggingClient) InfoTemplate(template string, fields map[string]interface{}) error {
+	return lc.logTemplate(support_domain.INFO, template, fields)
+}
+
+// Log a TRACE level message rendered from a template, like InfoTemplate
+func (lc LoggingClient) TraceTemplate(template string, fields map[string]interface{}) error {
+	return lc.logTemplate(support_domain.TRACE, template, fields)
+}
+
+// Log a DEBUG level message rendered from a template, like InfoTemplate
+func (lc LoggingClient) DebugTemplate(template string, fields map[string]interface{}) error {
+	return lc.logTemplate(support_domain.DEBUG, template, fields)
+}
+
+// Log a WARN level message rendered from a template, like InfoTemplate
+func (lc LoggingClient) WarnTemplate(template string, fields map[string]interface{}) error {
+	return lc.logTemplate(support_domain.WARN, template, fields)
+}
+
+// Log an ERROR level message rendered from a template, like InfoTemplate
+func (lc LoggingClient) ErrorTemplate(template string, fields map[string]interface{}) error {
+	return lc.logTemplate(support_domain.ERROR, template, fields)
+}
+
+func (lc LoggingClient) logTemplate(logLevel support_domain.LogLevel, template string, fields map[string]interface{}) error {
+	if !lc.IsEnabled(logLevel) {
+		return nil
+	}
+
+	msg, missing := renderTemplate(template, fields)
+	lc = lc.WithFields(fields)
+	if len(missing) > 0 {
+		lc.fields["templateWarning"] = "no value for " + strings.Join(missing, ", ")
+	}
+	return lc.log(logLevel, msg, nil)
+}
+
+// Replace each {name} in the template with the value of the field, keeping placeholders
+// without a value as they are. Also returns the names of those placeholders.
+func renderTemplate(template string, fields map[string]interface{}) (string, []string) {
+	var buf strings.Builder
+	var missing []string
+	for {
+		start := strings.IndexByte(template, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(template[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		name := template[start+1 : end]
+		buf.WriteString(template[:start])
+		if value, ok := fields[name]; ok {
+			buf.WriteString(fmt.Sprint(value))
+		} else {
+			buf.WriteString(template[start : end+1])
+			missing = append(missing, name)
+		}
+		template = template[end+1:]
+	}
+	buf.WriteString(template)
+	return buf.String(), missing
+}
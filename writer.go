@@ -0,0 +1,54 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"github.com/edgexfoundry/support-domain-go"
+	"io"
+	"strings"
+)
+
+// Get a writer that logs everything written to it at the given level, so the client
+// can be handed to libraries that log to an io.Writer, for example:
+//
+//	stdlog.New(lc.Writer(support_domain.ERROR), "", 0)
+func (lc LoggingClient) Writer(logLevel support_domain.LogLevel) io.Writer {
+	return levelWriter{lc: lc, logLevel: logLevel}
+}
+
+type levelWriter struct {
+	lc       LoggingClient
+	logLevel support_domain.LogLevel
+}
+
+// Log each line written as a separate message
+func (w levelWriter) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	// A trailing newline ends the last line rather than starting an empty one
+	lines := strings.Split(strings.TrimSuffix(string(p), "\n"), "\n")
+	for _, line := range lines {
+		if err := w.lc.log(w.logLevel, line, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
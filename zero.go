@@ -0,0 +1,59 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"sync"
+)
+
+// Client standing in for LoggingClient values that weren't made by a constructor
+var (
+	stdOutOnce   sync.Once
+	stdOutClient LoggingClient
+)
+
+// Get lc, or if it is a zero value (say a struct field that NewClient was never assigned to)
+// a client logging everything to stdout in its place, so using it doesn't panic.
+// The labels, fields and service name given to the zero value are kept, and so are the
+// settings Audit and the final logs of Fatal and Panic change on their copy. Zero values share
+// the one stdout client, so settings changed through one (like SetLogLevel) apply to all.
+func (lc LoggingClient) initialized() LoggingClient {
+	if lc.mutex != nil {
+		return lc
+	}
+
+	stdOutOnce.Do(func() {
+		stdOutClient, _ = New("")
+	})
+	client := stdOutClient
+	client.owningServiceName = lc.owningServiceName
+	client.labels = lc.labels
+	client.fields = lc.fields
+	client.baseCorrelationID = lc.baseCorrelationID
+	client.Synchronous = lc.Synchronous
+	client.BatchSize = lc.BatchSize
+	client.allLevels = lc.allLevels
+	client.stdOutLevel = lc.stdOutLevel
+	client.fileLevel = lc.fileLevel
+	client.remoteLevel = lc.remoteLevel
+	client.remoteInclude = lc.remoteInclude
+	client.remoteExclude = lc.remoteExclude
+	client.sampler = lc.sampler
+	client.deduplicator = lc.deduplicator
+	return client
+}
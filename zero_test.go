@@ -0,0 +1,105 @@
+/*******************************************************************************
+ * Copyright 2017 Dell Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License. You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software distributed under the License
+ * is distributed on an "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing permissions and limitations under
+ * the License.
+ *
+ * @microservice: support-logging-client-go library
+ * @author: Ryan Comer, Dell
+ * @version: 0.5.0
+ *******************************************************************************/
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/support-domain-go"
+)
+
+func TestZeroValueClient(t *testing.T) {
+	// Log only errors, so the test doesn't fill stdout
+	var lc LoggingClient
+	if err := lc.SetLogLevel(support_domain.ERROR); err != nil {
+		t.Fatal(err)
+	}
+	defer lc.SetLogLevel(support_domain.TRACE)
+
+	lc.Info("info")
+	lc.With("label").WithField("key", "value").Infof("info %d", 1)
+	lc.InfoCtx(context.Background(), "info")
+	lc.InfoTemplate("info {key}", map[string]interface{}{"key": 1})
+	lc.Writer(support_domain.INFO).Write([]byte("info\n"))
+	if lc.IsEnabled(support_domain.INFO) {
+		t.Error("INFO enabled after SetLogLevel(ERROR)")
+	}
+
+	if err := lc.SetLevelFor(support_domain.WARN, time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	lc.SetRemoteURL("")
+	if err := lc.SetLogFile(""); err != nil {
+		t.Fatal(err)
+	}
+	lc.AddHook(&MemorySink{})
+	if err := lc.AddFileSink(filepath.Join(t.TempDir(), "errors.log"), support_domain.ERROR); err != nil {
+		t.Fatal(err)
+	}
+	if lc.Describe() == "" {
+		t.Error("no description")
+	}
+	lc.Stats()
+
+	logger := slog.New(NewSlogHandler(lc))
+	logger.Info("info")
+
+	func() {
+		defer lc.RecoverAndContinue()
+		panic("recovered")
+	}()
+
+	if err := lc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.WaitForSends(time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := lc.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestZeroValueAudit(t *testing.T) {
+	server := newCollectingServer(t)
+	var lc LoggingClient
+	memory := &MemorySink{}
+	lc.AddHook(memory)
+	if err := lc.SetLogLevel(support_domain.ERROR); err != nil {
+		t.Fatal(err)
+	}
+	defer lc.SetLogLevel(support_domain.TRACE)
+	lc.SetRemoteURL(server.URL)
+	defer lc.SetRemoteURL("")
+
+	// Logged whatever the level, and sent before Audit returns
+	if err := lc.Audit("delete", "admin", "device/thermostat", "success"); err != nil {
+		t.Fatal(err)
+	}
+	if got := server.received(); len(got) != 1 || !strings.HasPrefix(got[0], "audit: admin delete") {
+		t.Errorf("sent %q", got)
+	}
+	if !memory.ContainsMessage(support_domain.INFO, "audit: admin delete") {
+		t.Error("audit entry not logged")
+	}
+}